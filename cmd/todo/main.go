@@ -1,17 +1,40 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
 	"github.com/nirabyte/todo/internal/app"
+	"github.com/nirabyte/todo/internal/cli"
+	"github.com/nirabyte/todo/internal/config"
 )
 
 func main() {
-	app := app.New()
+	if len(os.Args) > 1 && cli.IsCommand(os.Args[1]) {
+		if err := cli.Run(os.Args[1], os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	settings, err := config.LoadSettings()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	flag.BoolVar(&settings.Accessible, "accessible", settings.Accessible, "disable animations and blinking, label task state with text")
+	flag.Parse()
+
+	app, err := app.New(settings)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 	if err := app.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
-
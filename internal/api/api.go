@@ -0,0 +1,280 @@
+// Package api is a small REST interface to the task list for editors,
+// launchers and scripts.
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nirabyte/todo/internal/config"
+	"github.com/nirabyte/todo/internal/models"
+)
+
+type server struct {
+	mu      sync.Mutex
+	token   string
+	changed func()
+}
+
+// Handler serves the API on the data file. Requests must carry token as a
+// bearer token unless it is empty. changed is called after every write,
+// to tell other programs to reload.
+//
+//	GET    /v1/tasks                 list, filtered by ?done=true|false and ?tag=
+//	POST   /v1/tasks                 create from {"title", "due", "priority"}
+//	GET    /v1/tasks/{id}            one task
+//	PATCH  /v1/tasks/{id}            change title, done, due or priority
+//	POST   /v1/tasks/{id}/complete   mark done
+//	DELETE /v1/tasks/{id}            delete
+func Handler(token string, changed func()) http.Handler {
+	s := &server{token: token, changed: changed}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/tasks", s.auth(s.list))
+	mux.HandleFunc("POST /v1/tasks", s.auth(s.create))
+	mux.HandleFunc("GET /v1/tasks/{id}", s.auth(s.get))
+	mux.HandleFunc("PATCH /v1/tasks/{id}", s.auth(s.update))
+	mux.HandleFunc("POST /v1/tasks/{id}/complete", s.auth(s.complete))
+	mux.HandleFunc("DELETE /v1/tasks/{id}", s.auth(s.delete))
+	return mux
+}
+
+// input is the body of a create or update. Missing fields are left alone.
+// Due is an RFC 3339 time, a duration from now such as "2h" or "1d", or
+// "" to clear it.
+type input struct {
+	Title    *string `json:"title"`
+	Done     *bool   `json:"done"`
+	Due      *string `json:"due"`
+	Priority *string `json:"priority"`
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+func (s *server) auth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.token != "" {
+			got, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) != 1 {
+				fail(w, http.StatusUnauthorized, errors.New("missing or wrong token"))
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+func reply(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func fail(w http.ResponseWriter, status int, err error) {
+	reply(w, status, apiError{err.Error()})
+}
+
+func load() (models.AppData, error) {
+	data, err := models.ReadData()
+	if errors.Is(err, os.ErrNotExist) {
+		return models.AppData{}, nil
+	}
+	return data, err
+}
+
+func (s *server) list(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	data, err := load()
+	s.mu.Unlock()
+	if err != nil {
+		fail(w, http.StatusInternalServerError, err)
+		return
+	}
+	tasks := []models.Task{}
+	done := r.URL.Query().Get("done")
+	tag := strings.ToLower(strings.TrimPrefix(r.URL.Query().Get("tag"), "#"))
+	for _, t := range data.Tasks {
+		if done != "" && strconv.FormatBool(t.Done) != done {
+			continue
+		}
+		if tag != "" && !slices.Contains(t.Tags, tag) {
+			continue
+		}
+		tasks = append(tasks, t)
+	}
+	reply(w, http.StatusOK, tasks)
+}
+
+func (s *server) create(w http.ResponseWriter, r *http.Request) {
+	var in input
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		fail(w, http.StatusBadRequest, err)
+		return
+	}
+	if in.Title == nil || strings.TrimSpace(*in.Title) == "" {
+		fail(w, http.StatusBadRequest, errors.New("title is required"))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := load()
+	if err != nil {
+		fail(w, http.StatusInternalServerError, err)
+		return
+	}
+	now := time.Now()
+	t := models.Task{ID: now.UnixNano(), CreatedAt: now}
+	if err := apply(&t, in, now); err != nil {
+		fail(w, http.StatusBadRequest, err)
+		return
+	}
+	data.Tasks = append(data.Tasks, t)
+	if err := s.write(data); err != nil {
+		fail(w, http.StatusInternalServerError, err)
+		return
+	}
+	reply(w, http.StatusCreated, t)
+}
+
+func (s *server) get(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, i, ok := s.find(w, r)
+	if ok {
+		reply(w, http.StatusOK, data.Tasks[i])
+	}
+}
+
+func (s *server) update(w http.ResponseWriter, r *http.Request) {
+	var in input
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		fail(w, http.StatusBadRequest, err)
+		return
+	}
+	s.change(w, r, func(t *models.Task, now time.Time) error {
+		return apply(t, in, now)
+	})
+}
+
+func (s *server) complete(w http.ResponseWriter, r *http.Request) {
+	done := true
+	s.change(w, r, func(t *models.Task, now time.Time) error {
+		return apply(t, input{Done: &done}, now)
+	})
+}
+
+func (s *server) delete(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, i, ok := s.find(w, r)
+	if !ok {
+		return
+	}
+	data.Tasks = slices.Delete(data.Tasks, i, i+1)
+	if err := s.write(data); err != nil {
+		fail(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// change applies fn to the task named in the path and writes the list.
+func (s *server) change(w http.ResponseWriter, r *http.Request, fn func(t *models.Task, now time.Time) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, i, ok := s.find(w, r)
+	if !ok {
+		return
+	}
+	if err := fn(&data.Tasks[i], time.Now()); err != nil {
+		fail(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := s.write(data); err != nil {
+		fail(w, http.StatusInternalServerError, err)
+		return
+	}
+	reply(w, http.StatusOK, data.Tasks[i])
+}
+
+// find loads the list and locates the task named in the path, answering
+// the request itself when it can't.
+func (s *server) find(w http.ResponseWriter, r *http.Request) (models.AppData, int, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		fail(w, http.StatusBadRequest, fmt.Errorf("bad task id %q", r.PathValue("id")))
+		return models.AppData{}, 0, false
+	}
+	data, err := load()
+	if err != nil {
+		fail(w, http.StatusInternalServerError, err)
+		return data, 0, false
+	}
+	for i, t := range data.Tasks {
+		if t.ID == id {
+			return data, i, true
+		}
+	}
+	fail(w, http.StatusNotFound, fmt.Errorf("no task %d", id))
+	return data, 0, false
+}
+
+func (s *server) write(data models.AppData) error {
+	if err := models.WriteData(data); err != nil {
+		return err
+	}
+	if s.changed != nil {
+		s.changed()
+	}
+	return nil
+}
+
+// apply copies the fields set in in onto t, the same way the TUI's keys
+// change them.
+func apply(t *models.Task, in input, now time.Time) error {
+	if in.Title != nil {
+		title := strings.TrimSpace(*in.Title)
+		if title == "" {
+			return errors.New("title can't be empty")
+		}
+		t.SetTitle(title)
+	}
+	if in.Priority != nil {
+		p := strings.ToUpper(*in.Priority)
+		if p != "" && (len(p) != 1 || p < "A" || p > "Z") {
+			return fmt.Errorf("priority must be a letter A-Z, not %q", *in.Priority)
+		}
+		t.Priority = p
+	}
+	if in.Due != nil {
+		due := strings.TrimSpace(*in.Due)
+		if due == "" {
+			t.DueAt = time.Time{}
+		} else {
+			at, err := time.Parse(time.RFC3339, due)
+			if err != nil {
+				d, derr := config.ParseDuration(due)
+				if derr != nil {
+					return fmt.Errorf("due must be an RFC 3339 time or a duration, not %q", due)
+				}
+				at = now.Add(d)
+			}
+			t.SetDue(at, now)
+		}
+	}
+	if in.Done != nil && *in.Done != t.Done {
+		t.SetDone(*in.Done, now)
+	}
+	return nil
+}
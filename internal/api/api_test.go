@@ -0,0 +1,165 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nirabyte/todo/internal/models"
+)
+
+// call sends one request with token and decodes the reply into out, if
+// given, returning the status.
+func call(t *testing.T, srv *httptest.Server, method, path, token, body string, out any) int {
+	t.Helper()
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if out != nil && resp.StatusCode < 300 {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestEndpoints(t *testing.T) {
+	t.Chdir(t.TempDir())
+	changes := 0
+	srv := httptest.NewServer(Handler("s3cret", func() { changes++ }))
+	defer srv.Close()
+
+	var milk, mum models.Task
+	if code := call(t, srv, "POST", "/v1/tasks", "s3cret", `{"title": "Buy milk #home", "due": "2h", "priority": "b"}`, &milk); code != http.StatusCreated {
+		t.Fatalf("create = %d", code)
+	}
+	if milk.ID == 0 || milk.Priority != "B" || milk.DueAt.IsZero() || len(milk.Tags) != 1 || milk.Tags[0] != "home" {
+		t.Errorf("created %+v", milk)
+	}
+	if code := call(t, srv, "POST", "/v1/tasks", "s3cret", `{"title": "Call mum"}`, &mum); code != http.StatusCreated {
+		t.Fatalf("create = %d", code)
+	}
+	for _, body := range []string{`{}`, `{"title": "  "}`, `{"title": "x", "priority": "AB"}`, `{"title": "x", "due": "soon"}`, `not json`} {
+		if code := call(t, srv, "POST", "/v1/tasks", "s3cret", body, nil); code != http.StatusBadRequest {
+			t.Errorf("create %s = %d, want 400", body, code)
+		}
+	}
+
+	var got models.Task
+	path := fmt.Sprintf("/v1/tasks/%d", milk.ID)
+	if code := call(t, srv, "PATCH", path, "s3cret", `{"title": "Buy oat milk", "due": ""}`, &got); code != http.StatusOK {
+		t.Fatalf("patch = %d", code)
+	}
+	if got.Title != "Buy oat milk" || !got.DueAt.IsZero() || got.Priority != "B" {
+		t.Errorf("patched %+v", got)
+	}
+	if code := call(t, srv, "POST", path+"/complete", "s3cret", "", &got); code != http.StatusOK || !got.Done || got.CompletedAt.IsZero() {
+		t.Errorf("complete = %d, %+v", code, got)
+	}
+	if code := call(t, srv, "GET", path, "s3cret", "", &got); code != http.StatusOK || got.Title != "Buy oat milk" || !got.Done {
+		t.Errorf("get = %d, %+v", code, got)
+	}
+
+	for _, tc := range []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"Buy oat milk", "Call mum"}},
+		{"?done=true", []string{"Buy oat milk"}},
+		{"?done=false", []string{"Call mum"}},
+		{"?tag=%23home", nil},
+	} {
+		var tasks []models.Task
+		if code := call(t, srv, "GET", "/v1/tasks"+tc.query, "s3cret", "", &tasks); code != http.StatusOK {
+			t.Fatalf("list%s = %d", tc.query, code)
+		}
+		var titles []string
+		for _, task := range tasks {
+			titles = append(titles, task.Title)
+		}
+		if strings.Join(titles, ", ") != strings.Join(tc.want, ", ") {
+			t.Errorf("list%s = %q, want %q", tc.query, titles, tc.want)
+		}
+	}
+
+	if code := call(t, srv, "DELETE", path, "s3cret", "", nil); code != http.StatusNoContent {
+		t.Errorf("delete = %d", code)
+	}
+	for _, tc := range []struct{ method, path string }{
+		{"GET", path}, {"PATCH", path}, {"DELETE", path}, {"POST", path + "/complete"},
+	} {
+		if code := call(t, srv, tc.method, tc.path, "s3cret", "{}", nil); code != http.StatusNotFound {
+			t.Errorf("%s %s after delete = %d, want 404", tc.method, tc.path, code)
+		}
+	}
+	if code := call(t, srv, "GET", "/v1/tasks/abc", "s3cret", "", nil); code != http.StatusBadRequest {
+		t.Errorf("bad id = %d, want 400", code)
+	}
+
+	data, err := models.ReadData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Tasks) != 1 || data.Tasks[0].ID != mum.ID {
+		t.Errorf("file has %+v", data.Tasks)
+	}
+	// Two creates, a patch, a completion and a delete.
+	if changes != 5 {
+		t.Errorf("changed called %d times, want 5", changes)
+	}
+}
+
+func TestTagFilter(t *testing.T) {
+	t.Chdir(t.TempDir())
+	srv := httptest.NewServer(Handler("", nil))
+	defer srv.Close()
+	for _, title := range []string{"Buy milk #home", "Review PR #work", "Fix tap #Home"} {
+		if code := call(t, srv, "POST", "/v1/tasks", "", `{"title": "`+title+`"}`, nil); code != http.StatusCreated {
+			t.Fatalf("create = %d", code)
+		}
+	}
+	var tasks []models.Task
+	call(t, srv, "GET", "/v1/tasks?tag=%23home", "", "", &tasks)
+	if len(tasks) != 2 || tasks[0].Title != "Buy milk #home" || tasks[1].Title != "Fix tap #Home" {
+		t.Errorf("tag filter = %+v", tasks)
+	}
+}
+
+func TestTokenRequired(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := models.WriteData(models.AppData{Tasks: []models.Task{{ID: 1, Title: "Buy milk", CreatedAt: time.Now()}}}); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(Handler("s3cret", nil))
+	defer srv.Close()
+	for _, token := range []string{"", "wrong", "s3cre"} {
+		for _, tc := range []struct{ method, path string }{
+			{"GET", "/v1/tasks"}, {"POST", "/v1/tasks"}, {"GET", "/v1/tasks/1"},
+			{"PATCH", "/v1/tasks/1"}, {"POST", "/v1/tasks/1/complete"}, {"DELETE", "/v1/tasks/1"},
+		} {
+			if code := call(t, srv, tc.method, tc.path, token, `{"title": "x", "done": true}`, nil); code != http.StatusUnauthorized {
+				t.Errorf("token %q: %s %s = %d, want 401", token, tc.method, tc.path, code)
+			}
+		}
+	}
+	data, err := models.ReadData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Tasks) != 1 || data.Tasks[0].Title != "Buy milk" || data.Tasks[0].Done {
+		t.Errorf("unauthorised requests changed the list: %+v", data.Tasks)
+	}
+}
@@ -4,46 +4,134 @@ import (
 	"math/rand"
 	"time"
 
+	"github.com/charmbracelet/bubbles/cursor"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nirabyte/todo/internal/config"
+	"github.com/nirabyte/todo/internal/control"
+	"github.com/nirabyte/todo/internal/formats"
+	"github.com/nirabyte/todo/internal/history"
 	"github.com/nirabyte/todo/internal/models"
+	"github.com/nirabyte/todo/internal/notify"
+	"github.com/nirabyte/todo/internal/remote"
 	"github.com/nirabyte/todo/internal/styles"
 	"github.com/nirabyte/todo/internal/themes"
 )
 
 type App struct {
 	Model *models.Model
+
+	// flush waits for work that saves started in the background.
+	flush []func()
 }
 
-func New() *App {
+func New(settings config.Settings) (*App, error) {
 	ti := textinput.New()
 	ti.CharLimit = 256
 	ti.Width = 50
 	ti.Prompt = ""
+	if settings.Accessible {
+		ti.Cursor.SetMode(cursor.CursorStatic)
+	}
 
-	rand.Seed(time.Now().UnixNano())
+	notifier, err := notify.FromSettings(settings.Notifications)
+	if err != nil {
+		return nil, err
+	}
 
-	data := models.LoadData()
+	if len(settings.Reminders) > 0 {
+		models.DefaultReminders = settings.Reminders
+	}
+
+	data, err := models.LoadData()
+	if err != nil {
+		return nil, err
+	}
 	model := &models.Model{
 		Tasks:      data.Tasks,
 		State:      models.StateBrowse,
 		SortMode:   data.SortMode,
 		ThemeIndex: data.ThemeIndex,
 		TextInput:  ti,
+		Clock:      models.SystemClock{},
+		Rand:       rand.New(rand.NewSource(time.Now().UnixNano())),
+		Daemon:     control.Socket(config.DataFile),
+		Socket:     control.AppSocket(config.DataFile),
+		Notifier:   notifier,
+
+		Accessible:      settings.Accessible,
+		Celebrations:    settings.Celebrations,
+		DailyGoal:       settings.DailyGoal,
+		GoalDay:         data.GoalDay,
+		BoardColumns:    settings.Board.Columns,
+		OverdueInterval: time.Duration(settings.OverdueInterval),
+		FocusSettings: models.FocusSettings{
+			Work:           time.Duration(settings.Focus.Work),
+			ShortBreak:     time.Duration(settings.Focus.ShortBreak),
+			LongBreak:      time.Duration(settings.Focus.LongBreak),
+			LongBreakEvery: settings.Focus.LongBreakEvery,
+		},
 	}
 
 	if model.ThemeIndex >= len(themes.All) {
 		model.ThemeIndex = 0
 	}
-	styles.Update(themes.All[model.ThemeIndex])
+	styles.Update(themes.All[model.ThemeIndex], model.Accessible)
 	model.ApplySort()
 
-	return &App{Model: model}
+	if settings.Notes != "" {
+		notes := &formats.Notes{Path: config.ExpandPath(settings.Notes), State: formats.NotesState}
+		model.Notes = notes.Sync
+	}
+	app := &App{Model: model}
+	if settings.History.Enabled {
+		repo, err := history.Open(config.DataFile)
+		if err != nil {
+			return nil, err
+		}
+		// git is too slow to run on every keystroke's save, so commits
+		// happen in the background. Only the latest save waits its turn:
+		// a commit takes the file as it is by then anyway.
+		commits := make(chan models.AppData, 1)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for data := range commits {
+				model.Report("History", repo.Commit(data))
+			}
+		}()
+		model.OnSave = append(model.OnSave, func(data models.AppData) {
+			select {
+			case <-commits:
+			default:
+			}
+			commits <- data
+		})
+		app.flush = append(app.flush, func() {
+			close(commits)
+			<-done
+		})
+	}
+	if settings.Sync.URL != "" {
+		models.SyncClocks = true
+		client := &remote.Client{URL: settings.Sync.URL, Token: settings.Sync.Token}
+		model.Sync = client.Sync
+		model.SyncInterval = max(time.Duration(settings.Sync.Interval), 5*time.Second)
+	}
+	if len(model.OnSave) > 0 {
+		// Catch the hooks up with anything changed while the app was closed.
+		model.Save()
+	}
+
+	return app, nil
 }
 
 func (a *App) Run() error {
-	p := tea.NewProgram(a.Model, tea.WithAltScreen())
+	defer a.Model.Close()
+	p := tea.NewProgram(a.Model, tea.WithAltScreen(), tea.WithMouseCellMotion())
 	_, err := p.Run()
+	for _, flush := range a.flush {
+		flush()
+	}
 	return err
 }
-
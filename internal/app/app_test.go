@@ -0,0 +1,84 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nirabyte/todo/internal/api"
+	"github.com/nirabyte/todo/internal/config"
+	"github.com/nirabyte/todo/internal/control"
+	"github.com/nirabyte/todo/internal/history"
+	"github.com/nirabyte/todo/internal/models"
+)
+
+func TestHistoryCommitsInBackground(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	settings := config.DefaultSettings()
+	settings.History.Enabled = true
+
+	a, err := New(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.Model.Tasks[0].Title = "Renamed"
+	a.Model.Save()
+	for _, flush := range a.flush {
+		flush()
+	}
+
+	// The catch-up save at start may take the rename with it, since a
+	// commit takes the file as it is by then, so look at what was committed.
+	out, err := exec.Command("git", "--git-dir", history.Dir, "show", "HEAD:"+config.DataFile).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git show: %v: %s", err, out)
+	}
+	if !strings.Contains(string(out), `"Renamed"`) {
+		t.Errorf("committed\n%s", out)
+	}
+}
+
+func TestAPIWriteWhilePromptingSurvivesSave(t *testing.T) {
+	t.Chdir(t.TempDir())
+	a, err := New(config.DefaultSettings())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.Model.Init()
+	defer a.Model.Close()
+	srv := httptest.NewServer(api.Handler("", func() { control.Changed(config.DataFile) }))
+	defer srv.Close()
+
+	a.Model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	a.Model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Mine")})
+	resp, err := http.Post(srv.URL+"/v1/tasks", "application/json", strings.NewReader(`{"title": "From the API"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create: %s", resp.Status)
+	}
+	// The reload waits for the prompt, which the enter then closes.
+	a.Model.Update(models.ReloadMsg{})
+	a.Model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	data, err := models.ReadData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	titles := map[string]bool{}
+	for _, task := range data.Tasks {
+		titles[task.Title] = true
+	}
+	if !titles["Mine"] || !titles["From the API"] {
+		t.Errorf("saved %+v", data.Tasks)
+	}
+	if len(a.Model.Tasks) != len(data.Tasks) || a.Model.Tasks[a.Model.Cursor].Title != "Mine" {
+		t.Errorf("model has %+v, cursor %d", a.Model.Tasks, a.Model.Cursor)
+	}
+}
@@ -0,0 +1,70 @@
+// Package cli implements the todo subcommands that run without the TUI.
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/nirabyte/todo/internal/config"
+	"github.com/nirabyte/todo/internal/models"
+)
+
+type command struct {
+	summary string
+	run     func(args []string) error
+}
+
+var commands = map[string]command{}
+
+func register(name, summary string, run func(args []string) error) {
+	commands[name] = command{summary, run}
+}
+
+// IsCommand reports whether name is a subcommand rather than a TUI flag.
+func IsCommand(name string) bool {
+	_, ok := commands[name]
+	return ok || name == "help"
+}
+
+func Run(name string, args []string) error {
+	if name == "help" {
+		usage()
+		return nil
+	}
+	return commands[name].run(args)
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(os.Stderr, "Usage: todo [flags]          start the TUI")
+	fmt.Fprintln(os.Stderr, "       todo <command> [flags]")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, commands[name].summary)
+	}
+}
+
+// loadSettings reads the settings file and applies the defaults that live
+// in package variables.
+func loadSettings() (config.Settings, error) {
+	settings, err := config.LoadSettings()
+	if err != nil {
+		return settings, err
+	}
+	if len(settings.Reminders) > 0 {
+		models.DefaultReminders = settings.Reminders
+	}
+	models.SyncClocks = settings.Sync.URL != ""
+	return settings, nil
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	return flag.NewFlagSet("todo "+name, flag.ContinueOnError)
+}
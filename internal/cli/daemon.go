@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/nirabyte/todo/internal/config"
+	"github.com/nirabyte/todo/internal/control"
+	"github.com/nirabyte/todo/internal/daemon"
+	"github.com/nirabyte/todo/internal/notify"
+)
+
+func init() {
+	register("daemon", "send reminders in the background", runDaemon)
+}
+
+func runDaemon(args []string) error {
+	fs := newFlagSet("daemon")
+	unit := fs.Bool("unit", false, "print a systemd user unit for this directory and exit")
+	install := fs.Bool("install", false, "install the systemd user unit for this directory and exit")
+	status := fs.Bool("status", false, "report whether a daemon is running for this directory")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	socket := control.Socket(config.DataFile)
+	switch {
+	case *unit:
+		exe, err := os.Executable()
+		if err != nil {
+			return err
+		}
+		dir, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		fmt.Print(daemon.Unit(exe, dir))
+		return nil
+
+	case *install:
+		path, err := daemon.InstallUnit()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %s\nEnable it with: systemctl --user enable --now %s\n", path, filepath.Base(path))
+		return nil
+
+	case *status:
+		reply, err := control.Send(socket, control.CmdStatus)
+		if err != nil {
+			fmt.Println("not running")
+			return nil
+		}
+		fmt.Println(reply)
+		return nil
+	}
+
+	settings, err := loadSettings()
+	if err != nil {
+		return err
+	}
+	notifier, err := notify.FromSettings(settings.Notifications)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return daemon.Run(ctx, socket, notifier, time.Duration(settings.OverdueInterval))
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/nirabyte/todo/internal/config"
+	"github.com/nirabyte/todo/internal/control"
+	"github.com/nirabyte/todo/internal/history"
+)
+
+func init() {
+	register("sync", "pull and push the task history with a git remote", runSync)
+	register("log", "show the task history", runLog)
+}
+
+func runSync(args []string) error {
+	fs := newFlagSet("sync")
+	settings, err := loadSettings()
+	if err != nil {
+		return err
+	}
+	remote := fs.String("remote", settings.History.Remote, "git remote URL or path")
+	branch := fs.String("branch", settings.History.Branch, "branch to sync")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *remote == "" {
+		return fmt.Errorf("no remote: pass -remote or set history.remote in %s", config.SettingsFile)
+	}
+	repo, err := history.Open(config.DataFile)
+	if err != nil {
+		return err
+	}
+	summary, err := repo.Sync(*remote, *branch)
+	if err != nil {
+		return err
+	}
+	control.Changed(config.DataFile)
+	fmt.Println(summary)
+	return nil
+}
+
+func runLog(args []string) error {
+	fs := newFlagSet("log")
+	n := fs.Int("n", 20, "number of entries to show, 0 for all")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if _, err := os.Stat(history.Dir); errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("no history: set history.enabled in %s", config.SettingsFile)
+	}
+	repo, err := history.Open(config.DataFile)
+	if err != nil {
+		return err
+	}
+	return repo.Log(os.Stdout, *n)
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/nirabyte/todo/internal/config"
+	"github.com/nirabyte/todo/internal/models"
+)
+
+func TestSyncCommand(t *testing.T) {
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	if out, err := exec.Command("git", "init", "-q", "--bare", remote).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare: %v: %s", err, out)
+	}
+
+	// The first machine has the remote in its settings.
+	t.Chdir(t.TempDir())
+	settings := fmt.Sprintf(`{"history": {"enabled": true, "remote": %q}}`, remote)
+	if err := os.WriteFile(config.SettingsFile, []byte(settings), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := models.WriteData(models.AppData{Tasks: []models.Task{{ID: 1, Title: "Shared"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runSync(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// The second passes it as a flag.
+	t.Chdir(t.TempDir())
+	if err := runSync(nil); err == nil {
+		t.Error("sync without a remote succeeded")
+	}
+	if err := runSync([]string{"-remote", remote}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := models.ReadData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Tasks) != 1 || data.Tasks[0].Title != "Shared" {
+		t.Errorf("pulled %+v", data.Tasks)
+	}
+	if err := runLog(nil); err != nil {
+		t.Errorf("log: %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nirabyte/todo/internal/issues"
+	"github.com/nirabyte/todo/internal/models"
+)
+
+// runIssueImport handles `todo import github|gitlab`. Running it again
+// brings the titles and closed state of earlier imports up to date.
+func runIssueImport(service string, args []string) error {
+	fs := newFlagSet("import " + service)
+	repo := fs.String("repo", "", "repository as owner/name (GitLab: group/project)")
+	assignee := fs.String("assignee", "me", "only issues assigned to this user; me is the token's owner, empty for all")
+	token := fs.String("token", "", "access token (default $GITHUB_TOKEN or $GITLAB_TOKEN)")
+	apiURL := fs.String("api-url", "", "API address, for GitHub Enterprise or a self-hosted GitLab")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *repo == "" {
+		return errors.New("-repo is required")
+	}
+
+	var client issues.Client
+	switch service {
+	case "github":
+		if *token == "" {
+			*token = os.Getenv("GITHUB_TOKEN")
+		}
+		client = &issues.GitHub{BaseURL: *apiURL, Token: *token}
+	case "gitlab":
+		if *token == "" {
+			*token = os.Getenv("GITLAB_TOKEN")
+		}
+		client = &issues.GitLab{BaseURL: *apiURL, Token: *token}
+	}
+	if _, err := loadSettings(); err != nil {
+		return err
+	}
+
+	data, err := models.ReadData()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	var added, updated int
+	data.Tasks, added, updated, err = issues.Sync(ctx, client, *repo, *assignee, data.Tasks, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := writeData(data); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d new, updated %d\n", added, updated)
+	return nil
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nirabyte/todo/internal/config"
+	"github.com/nirabyte/todo/internal/formats"
+	"github.com/nirabyte/todo/internal/models"
+)
+
+func init() {
+	register("notes", "sync tasks with a Markdown notes file", runNotes)
+}
+
+func runNotes(args []string) error {
+	fs := newFlagSet("notes")
+	file := fs.String("file", "", "Markdown file to sync with (default: notes in "+config.SettingsFile+")")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	settings, err := loadSettings()
+	if err != nil {
+		return err
+	}
+	path := *file
+	if path == "" {
+		path = settings.Notes
+	}
+	if path == "" {
+		return fmt.Errorf("no notes file: pass -file or set notes in %s", config.SettingsFile)
+	}
+	path = config.ExpandPath(path)
+
+	data, err := models.ReadData()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	notes := &formats.Notes{Path: path, State: formats.NotesState}
+	tasks, changed, err := notes.Sync(data.Tasks, time.Now())
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return nil
+	}
+	data.Tasks = tasks
+	return writeData(data)
+}
@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nirabyte/todo/internal/models"
+)
+
+func init() {
+	register("report", "summarize tracked time per task, tag or day", runReport)
+}
+
+const dayLayout = "2006-01-02"
+
+// entry is time spent on one task on one day.
+type entry struct {
+	day  string
+	task models.Task
+	dur  time.Duration
+}
+
+func runReport(args []string) error {
+	fs := newFlagSet("report")
+	by := fs.String("by", "task", "group by task, tag or day")
+	asCSV := fs.Bool("csv", false, "write CSV instead of a table")
+	from := fs.String("from", "", "first day to include, as YYYY-MM-DD")
+	to := fs.String("to", "", "last day to include, as YYYY-MM-DD")
+	focus := fs.Bool("focus", false, "also count pomodoro sessions")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var start, end time.Time
+	var err error
+	if *from != "" {
+		if start, err = time.ParseInLocation(dayLayout, *from, time.Local); err != nil {
+			return fmt.Errorf("-from: %w", err)
+		}
+	}
+	if *to != "" {
+		if end, err = time.ParseInLocation(dayLayout, *to, time.Local); err != nil {
+			return fmt.Errorf("-to: %w", err)
+		}
+		end = end.AddDate(0, 0, 1)
+	}
+
+	data, err := models.ReadData()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	var entries []entry
+	for _, t := range data.Tasks {
+		intervals := t.Tracked
+		if *focus {
+			intervals = append(append([]models.Interval{}, intervals...), t.Sessions...)
+		}
+		for _, i := range intervals {
+			if i.End.IsZero() {
+				i.End = now
+			}
+			entries = append(entries, splitDays(t, i, start, end)...)
+		}
+	}
+
+	var header []string
+	var rows [][]string
+	var durs []time.Duration
+	switch *by {
+	case "task":
+		header = []string{"task"}
+		totals := map[int64]time.Duration{}
+		tasks := map[int64]models.Task{}
+		for _, e := range entries {
+			totals[e.task.ID] += e.dur
+			tasks[e.task.ID] = e.task
+		}
+		for _, id := range sortedKeys(totals) {
+			rows = append(rows, []string{tasks[id].Title})
+			durs = append(durs, totals[id])
+		}
+	case "tag":
+		header = []string{"tag"}
+		totals := map[string]time.Duration{}
+		for _, e := range entries {
+			if len(e.task.Tags) == 0 {
+				totals["(untagged)"] += e.dur
+			}
+			for _, tag := range e.task.Tags {
+				totals["#"+tag] += e.dur
+			}
+		}
+		for _, tag := range sortedKeys(totals) {
+			rows = append(rows, []string{tag})
+			durs = append(durs, totals[tag])
+		}
+	case "day":
+		header = []string{"day", "task"}
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].day < entries[j].day })
+		type key struct {
+			day string
+			id  int64
+		}
+		index := map[key]int{}
+		for _, e := range entries {
+			k := key{e.day, e.task.ID}
+			if n, ok := index[k]; ok {
+				durs[n] += e.dur
+				continue
+			}
+			index[k] = len(rows)
+			rows = append(rows, []string{e.day, e.task.Title})
+			durs = append(durs, e.dur)
+		}
+	default:
+		return fmt.Errorf("-by must be task, tag or day, not %q", *by)
+	}
+
+	if *asCSV {
+		w := csv.NewWriter(os.Stdout)
+		w.Write(append(header, "hours", "seconds"))
+		for n, row := range rows {
+			w.Write(append(row,
+				fmt.Sprintf("%.2f", durs[n].Hours()),
+				fmt.Sprint(int64(durs[n].Seconds()))))
+		}
+		w.Flush()
+		return w.Error()
+	}
+
+	if len(rows) == 0 {
+		fmt.Println("No tracked time.")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, h := range header {
+		fmt.Fprintf(w, "%s\t", strings.ToUpper(h))
+	}
+	fmt.Fprintln(w, "TIME")
+	var total time.Duration
+	for n, row := range rows {
+		for _, cell := range row {
+			fmt.Fprintf(w, "%s\t", cell)
+		}
+		fmt.Fprintf(w, "%s\n", formatHours(durs[n]))
+		total += durs[n]
+	}
+	if *by != "tag" {
+		for range header {
+			fmt.Fprint(w, "\t")
+		}
+		fmt.Fprintf(w, "%s\n", formatHours(total))
+	}
+	return w.Flush()
+}
+
+// splitDays cuts i at local midnights and at the [start, end) range, which
+// is open on either side when zero.
+func splitDays(t models.Task, i models.Interval, start, end time.Time) []entry {
+	if !start.IsZero() && i.Start.Before(start) {
+		i.Start = start
+	}
+	if !end.IsZero() && i.End.After(end) {
+		i.End = end
+	}
+	i.Start = i.Start.In(time.Local)
+	var out []entry
+	for i.Start.Before(i.End) {
+		y, m, d := i.Start.Date()
+		midnight := time.Date(y, m, d+1, 0, 0, 0, 0, time.Local)
+		stop := i.End
+		if midnight.Before(stop) {
+			stop = midnight
+		}
+		out = append(out, entry{i.Start.Format(dayLayout), t, stop.Sub(i.Start)})
+		i.Start = stop
+	}
+	return out
+}
+
+// sortedKeys orders the keys of totals by time spent, most first.
+func sortedKeys[K comparable](totals map[K]time.Duration) []K {
+	keys := make([]K, 0, len(totals))
+	for k := range totals {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if totals[keys[i]] != totals[keys[j]] {
+			return totals[keys[i]] > totals[keys[j]]
+		}
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+	return keys
+}
+
+func formatHours(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
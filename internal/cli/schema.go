@@ -0,0 +1,20 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/nirabyte/todo/internal/models"
+)
+
+func init() {
+	register("schema", "print the JSON Schema of the data file", runSchema)
+}
+
+func runSchema(args []string) error {
+	fs := newFlagSet("schema")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	_, err := os.Stdout.Write(models.Schema)
+	return err
+}
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/nirabyte/todo/internal/api"
+	"github.com/nirabyte/todo/internal/config"
+	"github.com/nirabyte/todo/internal/control"
+	"github.com/nirabyte/todo/internal/formats"
+	"github.com/nirabyte/todo/internal/models"
+)
+
+func init() {
+	register("serve", "serve tasks to other apps over HTTP", runServe)
+}
+
+func runServe(args []string) error {
+	fs := newFlagSet("serve")
+	addr := fs.String("addr", "127.0.0.1:8765", "address to listen on")
+	socket := fs.String("socket", "", "listen on this Unix socket instead of -addr")
+	ics := fs.Bool("ics", false, "serve an iCalendar feed at /todos.ics")
+	rest := fs.Bool("api", false, "serve the REST API under /v1/")
+	token := fs.String("token", "", "token for the API and feed (default: api.token in "+config.SettingsFile+")")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if !*ics && !*rest {
+		return errors.New("nothing to serve: pass -api, -ics or both")
+	}
+	settings, err := loadSettings()
+	if err != nil {
+		return err
+	}
+
+	if *token == "" {
+		*token = settings.API.Token
+	}
+	// A socket is guarded by its file permissions; a port is open to
+	// every local user, so it always needs a token.
+	if *token == "" && *socket == "" {
+		b := make([]byte, 16)
+		rand.Read(b)
+		*token = hex.EncodeToString(b)
+		log.Printf("Token for this run: %s (set api.token in %s to keep one)", *token, config.SettingsFile)
+	}
+
+	mux := http.NewServeMux()
+	if *ics {
+		mux.HandleFunc("GET /todos.ics", feedAuth(*token, serveICS))
+	}
+	if *rest {
+		mux.Handle("/v1/", api.Handler(*token, func() { control.Changed(config.DataFile) }))
+	}
+
+	var l net.Listener
+	if *socket != "" {
+		l, err = control.Listen(*socket)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(*socket)
+		log.Printf("serving on unix:%s", *socket)
+	} else {
+		l, err = net.Listen("tcp", *addr)
+		if err != nil {
+			return err
+		}
+		log.Printf("serving on http://%s", l.Addr())
+		if *ics {
+			log.Printf("calendar feed: http://%s/todos.ics?token=%s", l.Addr(), *token)
+		}
+	}
+	return http.Serve(l, mux)
+}
+
+// feedAuth checks the token of a calendar feed request. Calendar apps
+// subscribe by URL alone, so the token may come in the query instead of
+// an Authorization header.
+func feedAuth(token string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if token != "" {
+			got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok {
+				got = r.URL.Query().Get("token")
+			}
+			if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				http.Error(w, "missing or wrong token", http.StatusUnauthorized)
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+// serveICS exports the data file afresh on every request, so calendar apps
+// that poll the feed see changes without a restart.
+func serveICS(w http.ResponseWriter, r *http.Request) {
+	data, err := models.ReadData()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	format, _ := formats.Lookup("ics")
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="todos.ics"`)
+	if err := format.Export(w, data.Tasks, formats.Options{}); err != nil {
+		log.Printf("export: %v", err)
+	}
+}
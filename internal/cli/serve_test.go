@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nirabyte/todo/internal/models"
+)
+
+func TestFeedNeedsToken(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := models.WriteData(models.AppData{Tasks: []models.Task{{ID: 1, Title: "Pay rent"}}}); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(feedAuth("s3cret", serveICS))
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		query, header string
+		want          int
+	}{
+		{"", "", http.StatusUnauthorized},
+		{"?token=wrong", "", http.StatusUnauthorized},
+		{"?token=s3cret", "", http.StatusOK},
+		{"", "Bearer s3cret", http.StatusOK},
+		{"?token=s3cret", "Bearer wrong", http.StatusUnauthorized},
+	} {
+		req, _ := http.NewRequest("GET", srv.URL+"/todos.ics"+tc.query, nil)
+		if tc.header != "" {
+			req.Header.Set("Authorization", tc.header)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != tc.want {
+			t.Errorf("%q %q: status %d, want %d", tc.query, tc.header, resp.StatusCode, tc.want)
+		}
+		if leaked := strings.Contains(string(body), "Pay rent"); leaked != (tc.want == http.StatusOK) {
+			t.Errorf("%q %q: body %q", tc.query, tc.header, body)
+		}
+	}
+}
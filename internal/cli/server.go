@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/nirabyte/todo/internal/config"
+	"github.com/nirabyte/todo/internal/remote"
+)
+
+func init() {
+	register("server", "share the task list with other machines", runServer)
+}
+
+func runServer(args []string) error {
+	fs := newFlagSet("server")
+	addr := fs.String("addr", "127.0.0.1:8766", "address to listen on")
+	token := fs.String("token", "", "bearer token clients must send (default: sync.token in "+config.SettingsFile+")")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	settings, err := loadSettings()
+	if err != nil {
+		return err
+	}
+	if *token == "" {
+		*token = settings.Sync.Token
+	}
+	if *token == "" {
+		log.Print("warning: no token set, anyone who can reach the server can change the list")
+	}
+	log.Printf("serving %s on http://%s", config.DataFile, *addr)
+	return http.ListenAndServe(*addr, remote.Handler(remote.FileStore{}, *token))
+}
@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nirabyte/todo/internal/config"
+	"github.com/nirabyte/todo/internal/control"
+	"github.com/nirabyte/todo/internal/formats"
+	"github.com/nirabyte/todo/internal/history"
+	"github.com/nirabyte/todo/internal/models"
+)
+
+func init() {
+	register("import", "add tasks from another format", runImport)
+	register("export", "write tasks in another format", runExport)
+}
+
+func formatUsage() string {
+	return "file format: " + strings.Join(formats.Names(), ", ")
+}
+
+func runImport(args []string) error {
+	if len(args) > 0 && (args[0] == "github" || args[0] == "gitlab") {
+		return runIssueImport(args[0], args[1:])
+	}
+	fs := newFlagSet("import")
+	name := fs.String("format", "todotxt", formatUsage())
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if _, err := loadSettings(); err != nil {
+		return err
+	}
+	format, err := formats.Lookup(*name)
+	if err != nil {
+		return err
+	}
+	if format.Import == nil {
+		return fmt.Errorf("%s can only be exported", *name)
+	}
+
+	in := io.Reader(os.Stdin)
+	if path := fs.Arg(0); path != "" && path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	imported, err := format.Import(in)
+	if err != nil {
+		return err
+	}
+
+	data, err := models.ReadData()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	var added, updated int
+	data.Tasks, added, updated = format.Merge(data.Tasks, imported, time.Now())
+	if err := writeData(data); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d new, updated %d\n", added, updated)
+	return nil
+}
+
+func runExport(args []string) error {
+	fs := newFlagSet("export")
+	name := fs.String("format", "todotxt", formatUsage())
+	output := fs.String("o", "", "write to this file instead of stdout")
+	var opts formats.Options
+	fs.StringVar(&opts.GroupBy, "group", "", "md: group tasks by tag")
+	columns := fs.String("columns", "", "csv, jsonl: comma-separated columns (default "+strings.Join(formats.DefaultColumns, ",")+")")
+	tz := fs.String("tz", "Local", "csv, jsonl: time zone for timestamps, such as UTC or Europe/Paris")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *columns != "" {
+		for _, c := range strings.Split(*columns, ",") {
+			opts.Columns = append(opts.Columns, strings.TrimSpace(c))
+		}
+	}
+	loc, err := time.LoadLocation(*tz)
+	if err != nil {
+		return fmt.Errorf("-tz: %w", err)
+	}
+	opts.Location = loc
+	format, err := formats.Lookup(*name)
+	if err != nil {
+		return err
+	}
+	if _, err := loadSettings(); err != nil {
+		return err
+	}
+	data, err := models.ReadData()
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		return format.Export(os.Stdout, data.Tasks, opts)
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := format.Export(f, data.Tasks, opts); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeData saves data, with clocks if syncing with a server is set up,
+// records it in the history if that is on, and tells a running daemon and
+// app to pick it up.
+func writeData(data models.AppData) error {
+	settings, err := loadSettings()
+	if err != nil {
+		return err
+	}
+	if err := models.WriteDataClocks(data, settings.Sync.URL != ""); err != nil {
+		return err
+	}
+	if settings.History.Enabled {
+		// The data is saved by now, so a failed commit is only a warning.
+		repo, err := history.Open(config.DataFile)
+		if err == nil {
+			err = repo.Commit(data)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: history: %v\n", err)
+		}
+	}
+	control.Changed(config.DataFile)
+	return nil
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"os"
+	"testing"
+
+	"github.com/nirabyte/todo/internal/config"
+	"github.com/nirabyte/todo/internal/models"
+)
+
+func TestImportStampsClocksWhenSyncing(t *testing.T) {
+	t.Chdir(t.TempDir())
+	defer func() { models.SyncClocks = false }()
+
+	for _, syncing := range []bool{false, true} {
+		settings := `{}`
+		if syncing {
+			settings = `{"sync": {"url": "http://127.0.0.1:1"}}`
+		}
+		os.Remove(config.DataFile)
+		if err := os.WriteFile(config.SettingsFile, []byte(settings), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile("in.txt", []byte("(A) Pay rent +home\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		models.SyncClocks = false
+		if err := runImport([]string{"in.txt"}); err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := models.ReadData()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(data.Tasks) != 1 {
+			t.Fatalf("imported %+v", data.Tasks)
+		}
+		if stamped := len(data.Tasks[0].Clock) > 0; stamped != syncing {
+			t.Errorf("syncing %v: clock = %v", syncing, data.Tasks[0].Clock)
+		}
+	}
+}
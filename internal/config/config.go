@@ -3,9 +3,9 @@ package config
 import "time"
 
 const (
-	CheckAnimDuration  = 290 * time.Millisecond
-	DeleteAnimDuration = 200 * time.Millisecond
-	FPS                = 60
-	DataFile           = "todos.json"
+	CheckAnimDuration   = 290 * time.Millisecond
+	DeleteAnimDuration  = 200 * time.Millisecond
+	CelebrationDuration = 2500 * time.Millisecond
+	FPS                 = 60
+	DataFile            = "todos.json"
 )
-
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration is a time.Duration that reads and writes as a string such as
+// "1h30m" or "2d" in JSON.
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(FormatDuration(time.Duration(d)))
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+var dayUnits = regexp.MustCompile(`(\d+(?:\.\d+)?)([dw])`)
+
+// ParseDuration is time.ParseDuration extended with days ("d") and weeks
+// ("w"). A bare "0" is accepted too.
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	var err error
+	s = dayUnits.ReplaceAllStringFunc(s, func(m string) string {
+		parts := dayUnits.FindStringSubmatch(m)
+		n, perr := strconv.ParseFloat(parts[1], 64)
+		if perr != nil {
+			err = perr
+		}
+		if parts[2] == "w" {
+			n *= 7
+		}
+		return strconv.FormatFloat(n*24, 'f', -1, 64) + "h"
+	})
+	if err != nil {
+		return 0, err
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
+
+// FormatDuration writes d the way ParseDuration reads it, using days
+// when d is a whole number of them.
+func FormatDuration(d time.Duration) string {
+	if d != 0 && d%(24*time.Hour) == 0 {
+		return strconv.FormatInt(int64(d/(24*time.Hour)), 10) + "d"
+	}
+	return d.String()
+}
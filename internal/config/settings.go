@@ -0,0 +1,178 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const SettingsFile = "todo.config.json"
+
+// Settings are the user preferences read from SettingsFile. Keys missing
+// from the file keep their DefaultSettings value.
+type Settings struct {
+	// Accessible turns off animations and blinking and labels task state
+	// with text instead of color alone.
+	Accessible bool `json:"accessible"`
+
+	// Celebrations plays a full-screen effect when the list is cleared,
+	// the daily goal is met or a completion streak hits a milestone.
+	Celebrations bool `json:"celebrations"`
+	DailyGoal    int  `json:"dailyGoal"`
+
+	Notifications Notifications `json:"notifications"`
+	// Reminders are the default offsets before a deadline to alert at.
+	Reminders []Duration `json:"reminders"`
+	// OverdueInterval repeats the alert for overdue tasks; zero alerts once.
+	OverdueInterval Duration `json:"overdueInterval"`
+
+	Focus Focus `json:"focus"`
+
+	// Notes is a Markdown file whose checklist is kept in sync with the
+	// task list. "~/" expands to the home directory.
+	Notes string `json:"notes,omitempty"`
+
+	Board Board `json:"board"`
+
+	History History `json:"history"`
+	Sync    Sync    `json:"sync"`
+	API     API     `json:"api"`
+}
+
+// Board sets the columns the board view starts with: status (Todo, Doing,
+// Done), tag or priority.
+type Board struct {
+	Columns string `json:"columns"`
+}
+
+// API configures `todo serve -api`. Clients send Token as a bearer token.
+type API struct {
+	Token string `json:"token,omitempty"`
+}
+
+// Sync shares the list through a `todo server` at URL, checking in every
+// Interval.
+type Sync struct {
+	URL      string   `json:"url,omitempty"`
+	Token    string   `json:"token,omitempty"`
+	Interval Duration `json:"interval"`
+}
+
+// History commits the data file to a git repository on every save when
+// Enabled. Remote and Branch are what `todo sync` pulls and pushes.
+type History struct {
+	Enabled bool   `json:"enabled"`
+	Remote  string `json:"remote,omitempty"`
+	Branch  string `json:"branch,omitempty"`
+}
+
+// Focus sets the pomodoro cycle: a long break replaces every
+// LongBreakEvery-th short break.
+type Focus struct {
+	Work           Duration `json:"work"`
+	ShortBreak     Duration `json:"shortBreak"`
+	LongBreak      Duration `json:"longBreak"`
+	LongBreakEvery int      `json:"longBreakEvery"`
+}
+
+// Notifications names the places reminders can be sent and decides which
+// of them each task uses.
+type Notifications struct {
+	Sinks map[string]Sink `json:"sinks"`
+	// Routes send reminders for tasks with a tag to specific sinks.
+	Routes []Route `json:"routes"`
+	// Default sinks are used for tasks that match no route.
+	Default []string `json:"default"`
+}
+
+// Sink configures one notifier. Type is one of desktop, terminal,
+// command, webhook or email; the other fields apply to that type only.
+type Sink struct {
+	Type string `json:"type"`
+
+	Mode     string `json:"mode,omitempty"`     // terminal: bell, osc9 or osc777
+	Command  string `json:"command,omitempty"`  // command
+	URL      string `json:"url,omitempty"`      // webhook
+	Template string `json:"template,omitempty"` // webhook: json, slack, discord or ntfy
+
+	SMTP     string   `json:"smtp,omitempty"` // email: host:port
+	From     string   `json:"from,omitempty"`
+	To       []string `json:"to,omitempty"`
+	Username string   `json:"username,omitempty"`
+	Password string   `json:"password,omitempty"`
+}
+
+type Route struct {
+	Tag   string   `json:"tag"`
+	Sinks []string `json:"sinks"`
+}
+
+func DefaultSettings() Settings {
+	return Settings{
+		Celebrations: true,
+		Reminders:    []Duration{0},
+		Focus: Focus{
+			Work:           Duration(25 * time.Minute),
+			ShortBreak:     Duration(5 * time.Minute),
+			LongBreak:      Duration(15 * time.Minute),
+			LongBreakEvery: 4,
+		},
+		Notifications: Notifications{
+			Sinks:   map[string]Sink{"desktop": {Type: "desktop"}},
+			Default: []string{"desktop"},
+		},
+		Board:   Board{Columns: "status"},
+		History: History{Branch: "main"},
+		Sync:    Sync{Interval: Duration(30 * time.Second)},
+	}
+}
+
+// ExpandPath replaces a leading "~/" in path with the home directory.
+func ExpandPath(path string) string {
+	rest, ok := strings.CutPrefix(path, "~/")
+	if !ok {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, rest)
+}
+
+func LoadSettings() (Settings, error) {
+	s := DefaultSettings()
+	data, err := os.ReadFile(SettingsFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("%s: %w", SettingsFile, err)
+	}
+	if err := s.Focus.validate(); err != nil {
+		return s, fmt.Errorf("%s: %w", SettingsFile, err)
+	}
+	return s, nil
+}
+
+// validate rejects phases shorter than a second. A zero-length phase
+// would end as soon as it started, over and over.
+func (f Focus) validate() error {
+	phases := []struct {
+		name string
+		d    Duration
+	}{{"work", f.Work}, {"shortBreak", f.ShortBreak}, {"longBreak", f.LongBreak}}
+	for _, p := range phases {
+		if time.Duration(p.d) < time.Second {
+			return fmt.Errorf("focus.%s must be at least 1s, not %s", p.name, time.Duration(p.d))
+		}
+	}
+	return nil
+}
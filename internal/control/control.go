@@ -0,0 +1,116 @@
+// Package control is the line protocol spoken over the Unix sockets of
+// the reminder daemon and the running app. It is kept apart from both so
+// each can talk to the other without importing it.
+package control
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	CmdPing   = "ping"
+	CmdReload = "reload"
+	CmdStatus = "status"
+)
+
+const timeout = 200 * time.Millisecond
+
+// Socket returns the daemon's control socket for a data file. Each data
+// file gets its own daemon, so the path is derived from the file's
+// absolute path.
+func Socket(dataFile string) string {
+	return socket(dataFile, "")
+}
+
+// AppSocket returns the socket a running app takes reload requests on.
+func AppSocket(dataFile string) string {
+	return socket(dataFile, "-app")
+}
+
+func socket(dataFile, suffix string) string {
+	abs, err := filepath.Abs(dataFile)
+	if err != nil {
+		abs = dataFile
+	}
+	sum := sha256.Sum256([]byte(abs))
+	return filepath.Join(os.TempDir(), fmt.Sprintf("todo-%d-%s%s.sock", os.Getuid(), hex.EncodeToString(sum[:4]), suffix))
+}
+
+// Listen opens socket for its owner only, taking over a socket file left
+// behind by a process that died, but not one that is still answering.
+// Anything else at the path is left alone.
+func Listen(socket string) (net.Listener, error) {
+	l, err := listen(socket)
+	if err == nil {
+		return l, nil
+	}
+	if Alive(socket) {
+		return nil, fmt.Errorf("already running on %s", socket)
+	}
+	info, statErr := os.Lstat(socket)
+	if statErr != nil || info.Mode().Type() != os.ModeSocket {
+		return nil, err
+	}
+	os.Remove(socket)
+	return listen(socket)
+}
+
+// Changed tells the daemon and a running app that dataFile was rewritten,
+// so they reload it. Either may be absent.
+func Changed(dataFile string) {
+	Send(Socket(dataFile), CmdReload)
+	Send(AppSocket(dataFile), CmdReload)
+}
+
+// Send issues one command and returns the daemon's single-line reply.
+func Send(socket, cmd string) (string, error) {
+	conn, err := net.DialTimeout("unix", socket, timeout)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	if _, err := fmt.Fprintln(conn, cmd); err != nil {
+		return "", err
+	}
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(reply), nil
+}
+
+// Alive reports whether a daemon is answering on socket.
+func Alive(socket string) bool {
+	reply, err := Send(socket, CmdPing)
+	return err == nil && reply == "pong"
+}
+
+// Serve answers commands on l until it is closed, one command per
+// connection, passing each to handle.
+func Serve(l net.Listener, handle func(cmd string) string) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			conn.SetDeadline(time.Now().Add(time.Second))
+			line, err := bufio.NewReader(conn).ReadString('\n')
+			if err != nil {
+				return
+			}
+			fmt.Fprintln(conn, handle(strings.TrimSpace(line)))
+		}()
+	}
+}
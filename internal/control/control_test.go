@@ -0,0 +1,90 @@
+package control
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestListenTakesOverStaleSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "s.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Leave the file behind, as a process that died would.
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	l.Close()
+
+	l, err = Listen(socket)
+	if err != nil {
+		t.Fatalf("Listen over a stale socket: %v", err)
+	}
+	l.Close()
+}
+
+func TestListenRefusesLiveSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "s.sock")
+	l, err := Listen(socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go Serve(l, func(string) string { return "pong" })
+
+	if l2, err := Listen(socket); err == nil {
+		l2.Close()
+		t.Fatal("Listen took over a live socket")
+	}
+}
+
+func TestListenKeepsOtherFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(path, []byte("keep me"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if l, err := Listen(path); err == nil {
+		l.Close()
+		t.Fatal("Listen replaced a regular file")
+	}
+	if b, err := os.ReadFile(path); err != nil || string(b) != "keep me" {
+		t.Fatalf("file is now %q, %v", b, err)
+	}
+}
+
+func TestListenIsPrivate(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "s.sock")
+	l, err := Listen(socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	info, err := os.Stat(socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm&0o077 != 0 {
+		t.Errorf("socket mode %v, want no access for others", perm)
+	}
+}
+
+func TestListenCleansUp(t *testing.T) {
+	dir := t.TempDir()
+	socket := filepath.Join(dir, "s.sock")
+	l, err := Listen(socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := l.Addr().String(); got != socket {
+		t.Errorf("address %q, want %q", got, socket)
+	}
+	go Serve(l, func(string) string { return "pong" })
+	if reply, err := Send(socket, "ping"); err != nil || reply != "pong" {
+		t.Errorf("send = %q, %v", reply, err)
+	}
+	l.Close()
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("left behind %v", entries)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package control
+
+import "net"
+
+func listen(socket string) (net.Listener, error) {
+	return net.Listen("unix", socket)
+}
@@ -0,0 +1,54 @@
+//go:build unix
+
+package control
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+)
+
+// listen creates socket for its owner only. It binds inside a new private
+// directory, so no other user can connect before the socket is locked
+// down, and then links it into place. Unlike a rename, the link fails if
+// something is already at socket. The umask is process-wide, so it is
+// left alone.
+func listen(socket string) (net.Listener, error) {
+	dir, err := os.MkdirTemp(filepath.Dir(socket), ".todo-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	tmp := filepath.Join(dir, "s")
+	l, err := net.Listen("unix", tmp)
+	if err != nil {
+		return nil, err
+	}
+	ul := l.(*net.UnixListener)
+	ul.SetUnlinkOnClose(false)
+	if err := os.Chmod(tmp, 0o600); err != nil {
+		l.Close()
+		return nil, err
+	}
+	if err := os.Link(tmp, socket); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return &linkedListener{UnixListener: ul, path: socket}, nil
+}
+
+// linkedListener is a listener bound under another name that removes its
+// own path when closed.
+type linkedListener struct {
+	*net.UnixListener
+	path string
+}
+
+func (l *linkedListener) Close() error {
+	os.Remove(l.path)
+	return l.UnixListener.Close()
+}
+
+func (l *linkedListener) Addr() net.Addr {
+	return &net.UnixAddr{Name: l.path, Net: "unix"}
+}
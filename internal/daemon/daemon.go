@@ -0,0 +1,259 @@
+// Package daemon sends due-date notifications while the TUI is closed.
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/nirabyte/todo/internal/config"
+	"github.com/nirabyte/todo/internal/control"
+	"github.com/nirabyte/todo/internal/models"
+	"github.com/nirabyte/todo/internal/notify"
+)
+
+// PollInterval is how often the data file is checked for changes made by
+// something other than the TUI, which asks for a reload after every save.
+const PollInterval = 5 * time.Second
+
+type daemon struct {
+	reload   chan struct{}
+	notifier notify.Notifier
+	overdue  time.Duration
+
+	// mu guards the fields below against the control socket handlers.
+	mu      sync.Mutex
+	modTime time.Time
+	tasks   []models.Task
+
+	// sent remembers when each task was last alerted, so a TUI that saves
+	// a stale copy of the list cannot trigger the same alert again.
+	sent map[int64]time.Time
+	// hold keeps back a task's alert while it is being sent (zero) and,
+	// after a failed send, until it is time to retry.
+	hold map[int64]time.Time
+}
+
+// Run watches the data file and sends notifications through n until ctx
+// is done. Overdue tasks are reminded again every overdue interval, if it
+// is positive.
+func Run(ctx context.Context, socket string, n notify.Notifier, overdue time.Duration) error {
+	l, err := control.Listen(socket)
+	if err != nil {
+		return fmt.Errorf("daemon: %w", err)
+	}
+	defer os.Remove(socket)
+	defer l.Close()
+
+	d := &daemon{
+		reload:   make(chan struct{}, 1),
+		notifier: n,
+		overdue:  overdue,
+		sent:     map[int64]time.Time{},
+		hold:     map[int64]time.Time{},
+	}
+	go control.Serve(l, d.handle)
+	log.Printf("watching %s, control socket %s", config.DataFile, socket)
+
+	for {
+		d.mu.Lock()
+		d.load(false)
+		next := d.next()
+		d.mu.Unlock()
+
+		wait := PollInterval
+		if !next.IsZero() {
+			wait = min(wait, max(time.Until(next), 0))
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-d.reload:
+			timer.Stop()
+			d.mu.Lock()
+			d.load(true)
+			d.mu.Unlock()
+		case <-timer.C:
+		}
+		d.fire()
+	}
+}
+
+func (d *daemon) handle(cmd string) string {
+	switch cmd {
+	case control.CmdPing:
+		return "pong"
+	case control.CmdReload:
+		select {
+		case d.reload <- struct{}{}:
+		default:
+		}
+		return "ok"
+	case control.CmdStatus:
+		d.mu.Lock()
+		next := d.next()
+		d.mu.Unlock()
+		if !next.IsZero() {
+			return "next " + next.Format(time.RFC3339)
+		}
+		return "idle"
+	}
+	return "unknown command"
+}
+
+// load rereads the data file if it changed since the last read.
+func (d *daemon) load(force bool) {
+	info, err := os.Stat(config.DataFile)
+	if err != nil {
+		return
+	}
+	if !force && info.ModTime().Equal(d.modTime) {
+		return
+	}
+	data, err := models.ReadData()
+	if err != nil {
+		log.Printf("read %s: %v", config.DataFile, err)
+		return
+	}
+	d.modTime = info.ModTime()
+	d.tasks = data.Tasks
+}
+
+// alert returns t's next alert, skipping alerts this daemon has already
+// sent even if the file has not caught up yet, and holding back one being
+// sent or waiting to be retried.
+func (d *daemon) alert(t models.Task) time.Time {
+	at := t.NextAlert(d.overdue)
+	if sent, ok := d.sent[t.ID]; ok && !at.After(sent) {
+		return time.Time{}
+	}
+	hold, ok := d.hold[t.ID]
+	switch {
+	case !ok || at.IsZero():
+		return at
+	case hold.IsZero():
+		return time.Time{}
+	case at.Before(hold):
+		return hold
+	}
+	return at
+}
+
+func (d *daemon) next() time.Time {
+	var next time.Time
+	for _, t := range d.tasks {
+		at := d.alert(t)
+		if !at.IsZero() && (next.IsZero() || at.Before(next)) {
+			next = at
+		}
+	}
+	return next
+}
+
+// fire sends every alert that has come due and records the ones that went
+// out in the data file; a failed one is retried after models.AlertRetry.
+// The notifier runs without d.mu held, since it may block for a while and a
+// notification's buttons call back into action.
+func (d *daemon) fire() {
+	now := time.Now()
+	d.mu.Lock()
+	var due []notify.Notification
+	var tasks []models.Task
+	for _, t := range d.tasks {
+		at := d.alert(t)
+		if at.IsZero() || now.Before(at) {
+			continue
+		}
+		n := t.Notification(now)
+		id := t.ID
+		n.OnAction = func(key string) { d.action(id, key) }
+		due = append(due, n)
+		tasks = append(tasks, t)
+		d.hold[t.ID] = time.Time{}
+	}
+	d.mu.Unlock()
+	if len(due) == 0 {
+		return
+	}
+
+	errs := make([]error, len(due))
+	for i, n := range due {
+		errs[i] = d.notifier.Notify(n)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	ids := map[int64]bool{}
+	for i, t := range tasks {
+		if errs[i] != nil {
+			log.Printf("notify %q: %v", t.Title, errs[i])
+			d.hold[t.ID] = now.Add(models.AlertRetry)
+			continue
+		}
+		delete(d.hold, t.ID)
+		d.sent[t.ID] = now
+		ids[t.ID] = true
+	}
+	if len(ids) == 0 {
+		return
+	}
+	err := d.update(func(t *models.Task) bool {
+		if ids[t.ID] {
+			t.MarkAlerted(now)
+		}
+		return ids[t.ID]
+	})
+	if err != nil {
+		log.Printf("update %s: %v", config.DataFile, err)
+	}
+}
+
+// action applies a button pressed on a notification.
+func (d *daemon) action(id int64, key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	err := d.update(func(t *models.Task) bool {
+		return t.ID == id && t.ApplyAction(key, time.Now()) == nil
+	})
+	if err != nil {
+		log.Printf("update %s: %v", config.DataFile, err)
+	}
+	select {
+	case d.reload <- struct{}{}:
+	default:
+	}
+}
+
+// update applies fn to every task in a fresh copy of the file, so edits
+// made since the last load survive, and writes it back if fn changed any.
+func (d *daemon) update(fn func(t *models.Task) bool) error {
+	data, err := models.ReadData()
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	changed := false
+	for i := range data.Tasks {
+		if fn(&data.Tasks[i]) {
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	if err := models.WriteData(data); err != nil {
+		return err
+	}
+	d.load(true)
+	go control.Send(control.AppSocket(config.DataFile), control.CmdReload)
+	return nil
+}
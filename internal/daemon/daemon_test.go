@@ -0,0 +1,110 @@
+package daemon
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nirabyte/todo/internal/control"
+	"github.com/nirabyte/todo/internal/models"
+	"github.com/nirabyte/todo/internal/notify"
+)
+
+// notifierFunc lets a test act from inside Notify, as a notification
+// whose button is pressed straight away would.
+type notifierFunc func(notify.Notification) error
+
+func (f notifierFunc) Notify(n notify.Notification) error { return f(n) }
+
+func TestFireDoesNotHoldLockWhileNotifying(t *testing.T) {
+	t.Chdir(t.TempDir())
+	due := time.Now().Add(-time.Minute)
+	err := models.WriteData(models.AppData{Tasks: []models.Task{
+		{ID: 1, Title: "Pay rent", DueAt: due, LastAlert: due.Add(-time.Hour)},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := &daemon{reload: make(chan struct{}, 1), sent: map[int64]time.Time{}, hold: map[int64]time.Time{}}
+	var status string
+	d.notifier = notifierFunc(func(n notify.Notification) error {
+		status = d.handle(control.CmdStatus)
+		n.OnAction(models.ActionDone)
+		return nil
+	})
+	d.load(true)
+
+	done := make(chan struct{})
+	go func() {
+		d.fire()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("fire deadlocked calling the notifier")
+	}
+	if status != "idle" {
+		t.Errorf("status during notify = %q, want idle", status)
+	}
+
+	data, err := models.ReadData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := data.Tasks[0]
+	if !task.Done {
+		t.Error("the Done action was lost")
+	}
+	if !task.Notified || task.LastAlert.Before(due) {
+		t.Errorf("alert not recorded: notified %v, last %v", task.Notified, task.LastAlert)
+	}
+}
+
+func TestFireRetriesFailedSends(t *testing.T) {
+	t.Chdir(t.TempDir())
+	due := time.Now().Add(-time.Minute)
+	err := models.WriteData(models.AppData{Tasks: []models.Task{
+		{ID: 1, Title: "Pay rent", DueAt: due, LastAlert: due.Add(-time.Hour)},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := &notify.Recorder{}
+	rec.Fail(errors.New("smtp: connection refused"))
+	d := &daemon{reload: make(chan struct{}, 1), notifier: rec, sent: map[int64]time.Time{}, hold: map[int64]time.Time{}}
+	d.load(true)
+
+	d.fire()
+	data, err := models.ReadData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task := data.Tasks[0]; task.Notified || task.LastAlert.After(due) {
+		t.Errorf("failed alert recorded as sent: notified %v, last %v", task.Notified, task.LastAlert)
+	}
+	if next := d.next(); next.Before(time.Now().Add(models.AlertRetry - time.Second)) {
+		t.Errorf("retry at %v, want about %v from now", next, models.AlertRetry)
+	}
+
+	// Back up, but the retry isn't due yet.
+	rec.Fail(nil)
+	d.fire()
+	if len(rec.Sent()) != 0 {
+		t.Fatal("retried before AlertRetry was up")
+	}
+
+	d.hold[1] = time.Now().Add(-time.Second)
+	d.fire()
+	if len(rec.Sent()) != 1 {
+		t.Fatalf("sent %d notifications on retry, want 1", len(rec.Sent()))
+	}
+	data, err = models.ReadData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task := data.Tasks[0]; !task.Notified || task.LastAlert.Before(due) {
+		t.Errorf("retried alert not recorded: notified %v, last %v", task.Notified, task.LastAlert)
+	}
+}
@@ -0,0 +1,68 @@
+package daemon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// UnitName is the systemd unit for the daemon of the data file in dir.
+// Each directory gets its own, so installing one doesn't replace another.
+func UnitName(dir string) string {
+	sum := sha256.Sum256([]byte(dir))
+	return "todo-" + hex.EncodeToString(sum[:4]) + ".service"
+}
+
+// Unit renders a systemd user unit that runs the daemon for the data file
+// in dir. The data file is relative to the working directory, so the unit
+// pins it.
+func Unit(exe, dir string) string {
+	return fmt.Sprintf(`[Unit]
+Description=todo reminder daemon for %s
+After=graphical-session.target
+
+[Service]
+Type=simple
+WorkingDirectory=%s
+ExecStart=%s daemon
+Restart=on-failure
+
+[Install]
+WantedBy=default.target
+`, specifiers.Replace(dir), specifiers.Replace(dir), execQuote(exe))
+}
+
+// specifiers escapes systemd's % specifiers. WorkingDirectory= takes the
+// rest of the line as the path, spaces included, so that is all it needs.
+var specifiers = strings.NewReplacer("%", "%%")
+
+// execQuote makes s a single ExecStart= word: quoted, with C escapes, and
+// with the specifiers and variable references systemd expands doubled.
+func execQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "%", "%%", "$", "$$").Replace(s) + `"`
+}
+
+// InstallUnit writes the unit for the current executable and directory to
+// the user's systemd directory and returns its path.
+func InstallUnit() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	config, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(config, "systemd", "user", UnitName(dir))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, []byte(Unit(exe, dir)), 0644)
+}
@@ -0,0 +1,28 @@
+package daemon
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnitNamePerDirectory(t *testing.T) {
+	a, b := UnitName("/home/ann/work"), UnitName("/home/ann/home")
+	if a == b {
+		t.Errorf("both directories get %s", a)
+	}
+	if a != UnitName("/home/ann/work") || !strings.HasPrefix(a, "todo-") || !strings.HasSuffix(a, ".service") {
+		t.Errorf("unit name %s", a)
+	}
+}
+
+func TestUnitEscapesPaths(t *testing.T) {
+	unit := Unit(`/opt/my apps/to"do$1`, "/home/ann/My Lists/100%")
+	for _, want := range []string{
+		"WorkingDirectory=/home/ann/My Lists/100%%\n",
+		`ExecStart="/opt/my apps/to\"do$$1" daemon` + "\n",
+	} {
+		if !strings.Contains(unit, want) {
+			t.Errorf("unit lacks %q:\n%s", want, unit)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Package formats converts tasks to and from other tools' file formats.
+package formats
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nirabyte/todo/internal/models"
+)
+
+// Format reads and writes one file format. Import is nil for formats that
+// can only be exported. Update copies the fields the format carries from an
+// imported task onto the existing task with the same ID.
+type Format struct {
+	Import func(r io.Reader) ([]models.Task, error)
+	Export func(w io.Writer, tasks []models.Task, opts Options) error
+	Update func(dst *models.Task, src models.Task, now time.Time)
+}
+
+// Options tune an export. Formats ignore the options that don't apply.
+type Options struct {
+	// GroupBy is "" for one flat list or "tag" for a section per tag.
+	GroupBy string
+	// Columns picks the fields of tabular formats, in order; empty means
+	// all of DefaultColumns.
+	Columns []string
+	// Location is the time zone timestamps are written in; nil means
+	// local time.
+	Location *time.Location
+}
+
+var registry = map[string]Format{}
+
+func register(name string, f Format) {
+	registry[name] = f
+}
+
+// Lookup returns the format called name.
+func Lookup(name string) (Format, error) {
+	f, ok := registry[name]
+	if !ok {
+		return Format{}, fmt.Errorf("unknown format %q (have %s)", name, strings.Join(Names(), ", "))
+	}
+	return f, nil
+}
+
+// Names lists the registered formats.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Merge adds imported to tasks. A task whose ID is already in the list
+// updates that task instead of adding a copy; one without an ID gets a
+// new one.
+func (f Format) Merge(tasks, imported []models.Task, now time.Time) (merged []models.Task, added, updated int) {
+	index := map[int64]int{}
+	for i, t := range tasks {
+		index[t.ID] = i
+	}
+	next := now.UnixNano()
+	for _, t := range imported {
+		if i, ok := index[t.ID]; ok && t.ID != 0 {
+			f.Update(&tasks[i], t, now)
+			updated++
+			continue
+		}
+		if t.ID == 0 {
+			for _, taken := index[next]; taken; _, taken = index[next] {
+				next++
+			}
+			t.ID = next
+		}
+		if t.CreatedAt.IsZero() {
+			t.CreatedAt = now
+		}
+		index[t.ID] = len(tasks)
+		tasks = append(tasks, t)
+		added++
+	}
+	return tasks, added, updated
+}
+
+// updateCommon copies the fields every format carries. Reminders, snoozes
+// and logged time are left alone, and so is the due date when it only lost
+// precision on the way through the file.
+func updateCommon(dst *models.Task, src models.Task, now time.Time) {
+	dst.SetTitle(src.Title)
+	dst.Priority = src.Priority
+	if !src.CreatedAt.IsZero() && !sameDay(src.CreatedAt, dst.CreatedAt) {
+		dst.CreatedAt = src.CreatedAt
+	}
+	updateDoneDue(dst, src, now)
+}
+
+func updateDoneDue(dst *models.Task, src models.Task, now time.Time) {
+	if src.Done != dst.Done {
+		dst.Done = src.Done
+		dst.CompletedAt = time.Time{}
+		if src.Done {
+			dst.CompletedAt = src.CompletedAt
+			if dst.CompletedAt.IsZero() {
+				dst.CompletedAt = now
+			}
+		}
+	}
+	if !dst.DueAt.Truncate(time.Minute).Equal(src.DueAt.Truncate(time.Minute)) {
+		if src.DueAt.IsZero() {
+			dst.DueAt = time.Time{}
+		} else {
+			dst.SetDue(src.DueAt, now)
+		}
+	}
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Local().Date()
+	by, bm, bd := b.Local().Date()
+	return ay == by && am == bm && ad == bd
+}
@@ -0,0 +1,386 @@
+package formats
+
+import (
+	"bufio"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/nirabyte/todo/internal/config"
+	"github.com/nirabyte/todo/internal/models"
+)
+
+func init() {
+	register("ics", Format{Import: importICS, Export: exportICS, Update: updateICS})
+}
+
+const (
+	icsUTC  = "20060102T150405Z"
+	icsTime = "20060102T150405"
+	icsDate = "20060102"
+
+	// uidDomain ends the UIDs of exported tasks; an imported UID with it
+	// carries the task ID. Other UIDs are kept in Extra under uidKey.
+	uidDomain = "@todo"
+	uidKey    = "uid"
+)
+
+// exportICS writes tasks as an RFC 5545 calendar of VTODOs. Each reminder
+// offset becomes a VALARM relative to the due date.
+func exportICS(w io.Writer, tasks []models.Task, _ Options) error {
+	bw := bufio.NewWriter(w)
+	line := func(s string) {
+		// Lines are folded at 75 octets, without splitting a UTF-8 sequence.
+		for len(s) > 75 {
+			cut := 75
+			for cut > 0 && s[cut]&0xC0 == 0x80 {
+				cut--
+			}
+			bw.WriteString(s[:cut] + "\r\n")
+			s = " " + s[cut:]
+		}
+		bw.WriteString(s + "\r\n")
+	}
+
+	now := time.Now().UTC().Format(icsUTC)
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//nirabyte//todo//EN")
+	line("X-WR-CALNAME:todo")
+	for _, t := range tasks {
+		line("BEGIN:VTODO")
+		line("UID:" + icsUID(t))
+		line("DTSTAMP:" + now)
+		if !t.CreatedAt.IsZero() {
+			line("CREATED:" + t.CreatedAt.UTC().Format(icsUTC))
+		}
+		line("SUMMARY:" + icsEscape(t.Title))
+		if len(t.Tags) > 0 {
+			tags := make([]string, len(t.Tags))
+			for i, tag := range t.Tags {
+				tags[i] = icsEscape(tag)
+			}
+			line("CATEGORIES:" + strings.Join(tags, ","))
+		}
+		if p := icsPriority(t.Priority); p > 0 {
+			line("PRIORITY:" + strconv.Itoa(p))
+		}
+		if t.Done {
+			line("STATUS:COMPLETED")
+			if !t.CompletedAt.IsZero() {
+				line("COMPLETED:" + t.CompletedAt.UTC().Format(icsUTC))
+			}
+		} else {
+			line("STATUS:NEEDS-ACTION")
+		}
+		if !t.DueAt.IsZero() {
+			due := t.DueAt.UTC().Format(icsUTC)
+			if t.Recurrence != "" {
+				// A recurrence counts from DTSTART, which a task doesn't
+				// have apart from its deadline.
+				line("DTSTART:" + due)
+			}
+			line("DUE:" + due)
+			if t.Recurrence != "" {
+				line("RRULE:" + t.Recurrence)
+			}
+			offsets := t.Reminders
+			if len(offsets) == 0 {
+				offsets = models.DefaultReminders
+			}
+			for _, o := range offsets {
+				line("BEGIN:VALARM")
+				line("ACTION:DISPLAY")
+				line("DESCRIPTION:" + icsEscape(t.Title))
+				line("TRIGGER;RELATED=END:" + icsDuration(-time.Duration(o)))
+				line("END:VALARM")
+			}
+		}
+		line("END:VTODO")
+	}
+	line("END:VCALENDAR")
+	return bw.Flush()
+}
+
+func icsUID(t models.Task) string {
+	for _, kv := range t.Extra {
+		if kv.Key == uidKey {
+			return kv.Value
+		}
+	}
+	return strconv.FormatInt(t.ID, 10) + uidDomain
+}
+
+// icsPriority maps A-I onto iCalendar's 1 (highest) to 9; lower letters
+// all become 9.
+func icsPriority(p string) int {
+	if len(p) != 1 || p < "A" || p > "Z" {
+		return 0
+	}
+	return min(int(p[0]-'A')+1, 9)
+}
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+func icsEscape(s string) string {
+	return icsEscaper.Replace(s)
+}
+
+var icsUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+
+func icsUnescape(s string) string {
+	return icsUnescaper.Replace(s)
+}
+
+// notTagRune reports whether r can't be part of a hashtag, so a category
+// like "Home, Garden" becomes the tag home-garden.
+func notTagRune(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '_' && r != '-'
+}
+
+// icsSplit splits a list value at the commas that aren't escaped, before
+// any unescaping, so "a\,b,c" is the two values "a,b" and "c".
+func icsSplit(s string) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ',':
+			parts = append(parts, icsUnescape(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(parts, icsUnescape(s[start:]))
+}
+
+func icsDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	if d == 0 {
+		return "PT0S"
+	}
+	var b strings.Builder
+	b.WriteString(sign + "P")
+	if days := d / (24 * time.Hour); days > 0 {
+		fmt.Fprintf(&b, "%dD", days)
+		d -= days * 24 * time.Hour
+	}
+	if d > 0 {
+		b.WriteString("T")
+		if h := d / time.Hour; h > 0 {
+			fmt.Fprintf(&b, "%dH", h)
+			d -= h * time.Hour
+		}
+		if m := d / time.Minute; m > 0 {
+			fmt.Fprintf(&b, "%dM", m)
+			d -= m * time.Minute
+		}
+		if s := d / time.Second; s > 0 {
+			fmt.Fprintf(&b, "%dS", s)
+		}
+	}
+	return b.String()
+}
+
+var icsDurationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+func parseICSDuration(s string) (time.Duration, error) {
+	m := icsDurationPattern.FindStringSubmatch(s)
+	if m == nil || s == "P" || strings.HasSuffix(s, "T") {
+		return 0, fmt.Errorf("bad duration %q", s)
+	}
+	var d time.Duration
+	for i, unit := range []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second} {
+		if n, err := strconv.Atoi(m[i+2]); err == nil {
+			d += time.Duration(n) * unit
+		}
+	}
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
+
+// icsProperty is one content line: NAME;PARAM=VALUE:value.
+type icsProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+func parseICSLine(s string) (icsProperty, bool) {
+	// The value starts at the first colon outside a quoted parameter.
+	quoted := false
+	colon := -1
+	for i, r := range s {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return icsProperty{}, false
+	}
+	parts := strings.Split(s[:colon], ";")
+	p := icsProperty{name: strings.ToUpper(parts[0]), params: map[string]string{}, value: s[colon+1:]}
+	for _, param := range parts[1:] {
+		if k, v, ok := strings.Cut(param, "="); ok {
+			p.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return p, true
+}
+
+func (p icsProperty) time() (time.Time, error) {
+	if p.params["VALUE"] == "DATE" || len(p.value) == len(icsDate) {
+		return time.ParseInLocation(icsDate, p.value, time.Local)
+	}
+	if strings.HasSuffix(p.value, "Z") {
+		return time.Parse(icsUTC, p.value)
+	}
+	loc := time.Local
+	if tz := p.params["TZID"]; tz != "" {
+		if l, err := time.LoadLocation(tz); err == nil {
+			loc = l
+		}
+	}
+	return time.ParseInLocation(icsTime, p.value, loc)
+}
+
+// importICS reads the VTODOs of an iCalendar file. Events and other
+// components are skipped.
+func importICS(r io.Reader) ([]models.Task, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += text[1:]
+			continue
+		}
+		lines = append(lines, text)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var tasks []models.Task
+	var t *models.Task
+	var categories []string
+	inAlarm := false
+	for n, text := range lines {
+		p, ok := parseICSLine(text)
+		if !ok {
+			continue
+		}
+		fail := func(err error) ([]models.Task, error) {
+			return nil, fmt.Errorf("line %d: %s: %w", n+1, p.name, err)
+		}
+		switch {
+		case p.name == "BEGIN" && p.value == "VTODO":
+			t = &models.Task{}
+			categories = nil
+		case t == nil:
+		case p.name == "END" && p.value == "VTODO":
+			title := icsUnescape(t.Title)
+			have := map[string]bool{}
+			for _, tag := range models.ParseTags(title) {
+				have[tag] = true
+			}
+			for _, c := range categories {
+				tag := strings.ToLower(strings.Join(strings.FieldsFunc(c, notTagRune), "-"))
+				if tag != "" && !have[tag] {
+					have[tag] = true
+					title += " #" + tag
+				}
+			}
+			t.SetTitle(title)
+			tasks = append(tasks, *t)
+			t = nil
+		case p.name == "BEGIN" && p.value == "VALARM":
+			inAlarm = true
+		case p.name == "END" && p.value == "VALARM":
+			inAlarm = false
+		case inAlarm:
+			if p.name == "TRIGGER" && p.params["VALUE"] != "DATE-TIME" && p.params["RELATED"] != "START" {
+				if d, err := parseICSDuration(p.value); err == nil && d <= 0 {
+					t.Reminders = append(t.Reminders, config.Duration(-d))
+				}
+			}
+		case p.name == "UID":
+			if id, ok := strings.CutSuffix(p.value, uidDomain); ok {
+				if n, err := strconv.ParseInt(id, 10, 64); err == nil {
+					t.ID = n
+					continue
+				}
+			}
+			// Hash foreign UIDs so importing the same file twice matches up.
+			h := fnv.New64a()
+			h.Write([]byte(p.value))
+			t.ID = int64(h.Sum64() >> 1)
+			t.Extra = append(t.Extra, models.KeyValue{Key: uidKey, Value: p.value})
+		case p.name == "SUMMARY":
+			t.Title = p.value
+		case p.name == "CATEGORIES":
+			categories = append(categories, icsSplit(p.value)...)
+		case p.name == "PRIORITY":
+			if n, err := strconv.Atoi(p.value); err == nil && n >= 1 && n <= 9 {
+				t.Priority = string(rune('A' + n - 1))
+			}
+		case p.name == "STATUS":
+			t.Done = p.value == "COMPLETED"
+		case p.name == "RRULE":
+			t.Recurrence = p.value
+		case p.name == "DUE" || p.name == "DTSTART" || p.name == "COMPLETED" || p.name == "CREATED":
+			at, err := p.time()
+			if err != nil {
+				return fail(err)
+			}
+			switch p.name {
+			case "DUE":
+				t.DueAt = at
+			case "DTSTART":
+				// Without a DUE, the start is the closest thing to one.
+				if t.DueAt.IsZero() {
+					t.DueAt = at
+				}
+			case "COMPLETED":
+				t.CompletedAt = at
+				t.Done = true
+			case "CREATED":
+				t.CreatedAt = at
+			}
+		}
+	}
+	return tasks, nil
+}
+
+// updateICS copies what a VTODO carries. Alarms that only restate the
+// default reminders leave the task following the defaults.
+func updateICS(dst *models.Task, src models.Task, now time.Time) {
+	updateCommon(dst, src, now)
+	dst.Recurrence = src.Recurrence
+	if src.DueAt.IsZero() {
+		return
+	}
+	offsets := dst.Reminders
+	if len(offsets) == 0 {
+		offsets = models.DefaultReminders
+	}
+	if !slices.Equal(offsets, src.Reminders) {
+		dst.Reminders = src.Reminders
+	}
+}
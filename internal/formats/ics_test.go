@@ -0,0 +1,90 @@
+package formats
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nirabyte/todo/internal/models"
+)
+
+func TestExportICSRecurrenceHasStart(t *testing.T) {
+	due := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	var b bytes.Buffer
+	err := exportICS(&b, []models.Task{
+		{ID: 1, Title: "Standup", DueAt: due, Recurrence: "FREQ=WEEKLY;BYDAY=MO"},
+		{ID: 2, Title: "Once", DueAt: due},
+	}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	if n := strings.Count(out, "DTSTART:20260302T090000Z\r\n"); n != 1 {
+		t.Errorf("got %d DTSTART lines, want 1 for the recurring task:\n%s", n, out)
+	}
+
+	tasks, err := importICS(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 2 || !tasks[0].DueAt.Equal(due) || tasks[0].Recurrence != "FREQ=WEEKLY;BYDAY=MO" {
+		t.Errorf("round trip = %+v", tasks)
+	}
+}
+
+func TestImportICSStartWithoutDue(t *testing.T) {
+	tasks, err := importICS(strings.NewReader(strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VTODO",
+		"SUMMARY:Water plants",
+		"DTSTART:20260302T090000Z",
+		"RRULE:FREQ=DAILY",
+		"END:VTODO",
+		"END:VCALENDAR",
+	}, "\r\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	if len(tasks) != 1 || !tasks[0].DueAt.Equal(want) {
+		t.Errorf("tasks = %+v, want due %v", tasks, want)
+	}
+}
+
+func TestImportICSCategories(t *testing.T) {
+	tasks, err := importICS(strings.NewReader(strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VTODO",
+		`SUMMARY:Taxes`,
+		`CATEGORIES:Home\, Garden,Money\\Bills,Work`,
+		"END:VTODO",
+		"END:VCALENDAR",
+	}, "\r\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 1 {
+		t.Fatalf("got %d tasks", len(tasks))
+	}
+	want := []string{"home-garden", "money-bills", "work"}
+	if !slices.Equal(tasks[0].Tags, want) {
+		t.Errorf("tags = %q, want %q", tasks[0].Tags, want)
+	}
+}
+
+func TestICSSplit(t *testing.T) {
+	for in, want := range map[string][]string{
+		"a,b":       {"a", "b"},
+		`a\,b,c`:    {"a,b", "c"},
+		`a\\,b`:     {`a\`, "b"},
+		`a\;b\nc`:   {"a;b\nc"},
+		"":          {""},
+		`trailing\`: {`trailing\`},
+	} {
+		if got := icsSplit(in); !slices.Equal(got, want) {
+			t.Errorf("icsSplit(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
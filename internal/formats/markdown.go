@@ -0,0 +1,336 @@
+package formats
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nirabyte/todo/internal/models"
+)
+
+func init() {
+	register("md", Format{Import: importMarkdown, Export: exportMarkdown, Update: updateMarkdown})
+}
+
+const (
+	mdDate     = "2006-01-02"
+	mdDateTime = "2006-01-02 15:04"
+)
+
+var (
+	checkboxPattern = regexp.MustCompile(`^(\s*[-*+]\s+)\[([ xX])\]\s?(.*)$`)
+	mdIDPattern     = regexp.MustCompile(`\s*<!--\s*todo:(\d+)\s*-->`)
+	mdDuePattern    = regexp.MustCompile(`\s*\(due (\d{4}-\d{2}-\d{2}(?: \d{2}:\d{2})?)\)`)
+)
+
+// mdItem is a checkbox line: its bullet prefix, the task it describes and
+// whether it carried a task ID comment.
+type mdItem struct {
+	prefix string
+	task   models.Task
+	hasID  bool
+}
+
+func parseMarkdownItem(line string) (mdItem, bool) {
+	m := checkboxPattern.FindStringSubmatch(line)
+	if m == nil {
+		return mdItem{}, false
+	}
+	item := mdItem{prefix: m[1]}
+	text := m[3]
+	if id := mdIDPattern.FindStringSubmatch(text); id != nil {
+		if n, err := strconv.ParseInt(id[1], 10, 64); err == nil {
+			item.task.ID = n
+			item.hasID = true
+		}
+		text = mdIDPattern.ReplaceAllString(text, "")
+	}
+	if due := mdDuePattern.FindStringSubmatch(text); due != nil {
+		layout := mdDate
+		if len(due[1]) > len(mdDate) {
+			layout = mdDateTime
+		}
+		if at, err := time.ParseInLocation(layout, due[1], time.Local); err == nil {
+			item.task.DueAt = at
+			text = mdDuePattern.ReplaceAllString(text, "")
+		}
+	}
+	item.task.Done = m[2] != " "
+	item.task.SetTitle(strings.TrimSpace(text))
+	return item, true
+}
+
+// formatMarkdownItem is the checkbox line for t, without its bullet. The
+// ID comment is hidden when the Markdown is rendered.
+func formatMarkdownItem(t models.Task) string {
+	box := "[ ] "
+	if t.Done {
+		box = "[x] "
+	}
+	s := box + strings.Join(strings.Fields(t.Title), " ")
+	if !t.DueAt.IsZero() {
+		due := t.DueAt.Local()
+		if due.Hour() == 0 && due.Minute() == 0 {
+			s += " (due " + due.Format(mdDate) + ")"
+		} else {
+			s += " (due " + due.Format(mdDateTime) + ")"
+		}
+	}
+	return s + " <!-- todo:" + strconv.FormatInt(t.ID, 10) + " -->"
+}
+
+// importMarkdown reads the GitHub-style checkboxes in a Markdown file.
+// Everything else in it is ignored.
+func importMarkdown(r io.Reader) ([]models.Task, error) {
+	var tasks []models.Task
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if item, ok := parseMarkdownItem(scanner.Text()); ok && item.task.Title != "" {
+			tasks = append(tasks, item.task)
+		}
+	}
+	return tasks, scanner.Err()
+}
+
+// exportMarkdown writes tasks as a checklist, in one list or in a section
+// per task's first tag.
+func exportMarkdown(w io.Writer, tasks []models.Task, opts Options) error {
+	bw := bufio.NewWriter(w)
+	switch opts.GroupBy {
+	case "":
+		for _, t := range tasks {
+			bw.WriteString("- " + formatMarkdownItem(t) + "\n")
+		}
+	case "tag":
+		var order []string
+		groups := map[string][]models.Task{}
+		for _, t := range tasks {
+			tag := ""
+			if len(t.Tags) > 0 {
+				tag = t.Tags[0]
+			}
+			if _, ok := groups[tag]; !ok {
+				order = append(order, tag)
+			}
+			groups[tag] = append(groups[tag], t)
+		}
+		for i, tag := range order {
+			if i > 0 {
+				bw.WriteString("\n")
+			}
+			if tag == "" {
+				bw.WriteString("## Untagged\n\n")
+			} else {
+				bw.WriteString("## #" + tag + "\n\n")
+			}
+			for _, t := range groups[tag] {
+				bw.WriteString("- " + formatMarkdownItem(t) + "\n")
+			}
+		}
+	default:
+		return fmt.Errorf("cannot group by %q: use tag", opts.GroupBy)
+	}
+	return bw.Flush()
+}
+
+// updateMarkdown copies a checkbox's title, state and due date.
+func updateMarkdown(dst *models.Task, src models.Task, now time.Time) {
+	dst.SetTitle(src.Title)
+	updateDoneDue(dst, src, now)
+}
+
+// Notes keeps tasks in step with the checklist in a Markdown notes file.
+// Items in the file are linked to tasks by their ID comment, and only
+// linked tasks are written back, so the rest of the list stays out of the
+// notes. Each Sync reads the file before writing it, so edits made there
+// in the meantime are picked up rather than overwritten.
+type Notes struct {
+	Path string
+	// State is the file that keeps what each Sync left behind for the
+	// next, so a later run still knows which items it wrote. Empty keeps
+	// it in memory only.
+	State string
+
+	// last is each linked item as the previous Sync left it, by task ID,
+	// which tells an item edited in the file from a task edited in the
+	// app. Before the first Sync ever it is nil and the file wins.
+	last map[int64]string
+}
+
+// NotesState is where the app and todo notes keep Notes.State, next to
+// the data file.
+const NotesState = ".todo-notes.json"
+
+// states reads the State file: the last items of each notes file, by its
+// absolute path.
+func (n *Notes) states() (map[string]map[int64]string, error) {
+	states := map[string]map[int64]string{}
+	raw, err := os.ReadFile(n.State)
+	if errors.Is(err, os.ErrNotExist) {
+		return states, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &states); err != nil {
+		return nil, fmt.Errorf("%s: %w", n.State, err)
+	}
+	return states, nil
+}
+
+// remember stores last for file in the State file.
+func (n *Notes) remember(file string) error {
+	if n.State == "" {
+		return nil
+	}
+	states, err := n.states()
+	if err != nil {
+		return err
+	}
+	if last, ok := states[file]; ok && maps.Equal(last, n.last) {
+		return nil
+	}
+	states[file] = n.last
+	raw, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(n.State, raw, 0644)
+}
+
+// Sync merges the notes file into tasks and writes the result back.
+// Edited items update their task, new items become tasks linked to the
+// file, and an item that disappeared deletes its task. Going the other
+// way, a linked task's item is rewritten and a deleted task's item
+// dropped. The rest of the file is left alone, and a missing file changes
+// nothing. It reports whether tasks changed.
+func (n *Notes) Sync(tasks []models.Task, now time.Time) ([]models.Task, bool, error) {
+	old, err := os.ReadFile(n.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return tasks, false, nil
+	}
+	if err != nil {
+		return tasks, false, err
+	}
+	file, err := filepath.Abs(n.Path)
+	if err != nil {
+		return tasks, false, err
+	}
+	if n.last == nil && n.State != "" {
+		states, err := n.states()
+		if err != nil {
+			return tasks, false, err
+		}
+		n.last = states[file]
+	}
+	tasks = slices.Clone(tasks)
+
+	index := map[int64]int{}
+	for i, t := range tasks {
+		index[t.ID] = i
+	}
+	lines := strings.Split(strings.TrimSuffix(string(old), "\n"), "\n")
+	// What each line becomes: kept as is (0), dropped (-1), or the item of
+	// a linked task, by ID, or of the k-th new task, by -2-k.
+	const keep, drop = 0, -1
+	fate := make([]int64, len(lines))
+	seen := map[int64]bool{}
+	var added []models.Task
+	var prefixes []string
+	changed := false
+	for l, line := range lines {
+		item, ok := parseMarkdownItem(strings.TrimRight(line, "\r"))
+		if !ok || item.task.Title == "" {
+			continue
+		}
+		id := item.task.ID
+		if i, linked := index[id]; item.hasID && linked {
+			if seen[id] {
+				fate[l] = drop
+				continue
+			}
+			seen[id], fate[l] = true, id
+			t := &tasks[i]
+			if last, ok := n.last[id]; !ok || last != formatMarkdownItem(item.task) {
+				before := formatMarkdownItem(*t)
+				updateMarkdown(t, item.task, now)
+				changed = changed || formatMarkdownItem(*t) != before
+			}
+			if t.NotesFile != file {
+				t.NotesFile = file
+				changed = true
+			}
+			continue
+		}
+		if _, synced := n.last[id]; item.hasID && synced {
+			// Linked last time but gone from the list: deleted in the app.
+			fate[l] = drop
+			continue
+		}
+		item.task.NotesFile = file
+		fate[l] = int64(-2 - len(added))
+		added = append(added, item.task)
+		prefixes = append(prefixes, item.prefix)
+	}
+
+	// Linked tasks whose item is gone were deleted in the file.
+	kept := tasks[:0]
+	for _, t := range tasks {
+		if t.NotesFile == file && !seen[t.ID] {
+			changed = true
+			continue
+		}
+		kept = append(kept, t)
+	}
+	tasks = kept
+	first := len(tasks)
+	if len(added) > 0 {
+		tasks, _, _ = registry["md"].Merge(tasks, added, now)
+		changed = true
+	}
+
+	byID := map[int64]models.Task{}
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+	n.last = map[int64]string{}
+	var out []string
+	for l, line := range lines {
+		var t models.Task
+		prefix := ""
+		switch f := fate[l]; {
+		case f == keep:
+			out = append(out, line)
+			continue
+		case f == drop:
+			continue
+		case f < drop:
+			k := int(-2 - f)
+			t, prefix = tasks[first+k], prefixes[k]
+		default:
+			item, _ := parseMarkdownItem(strings.TrimRight(line, "\r"))
+			t, prefix = byID[f], item.prefix
+		}
+		n.last[t.ID] = formatMarkdownItem(t)
+		out = append(out, prefix+n.last[t.ID])
+	}
+
+	data := []byte(strings.Join(out, "\n") + "\n")
+	if !bytes.Equal(data, old) {
+		if err := os.WriteFile(n.Path, data, 0644); err != nil {
+			return tasks, changed, err
+		}
+	}
+	return tasks, changed, n.remember(file)
+}
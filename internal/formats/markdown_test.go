@@ -0,0 +1,138 @@
+package formats
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nirabyte/todo/internal/models"
+)
+
+func TestNotesRoundTrip(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "todo.md")
+	write := func(lines ...string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	read := func() string {
+		t.Helper()
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+	find := func(tasks []models.Task, title string) *models.Task {
+		for i := range tasks {
+			if tasks[i].Title == title {
+				return &tasks[i]
+			}
+		}
+		return nil
+	}
+
+	notes := &Notes{Path: path}
+	tasks := []models.Task{{ID: 1, Title: "Press 'n' to add a new task"}}
+	write("# Today", "", "- [ ] Buy milk", "- [ ] Call mum", "Some prose.")
+	tasks, changed, err := notes.Sync(tasks, now)
+	if err != nil || !changed || len(tasks) != 3 {
+		t.Fatalf("first sync = %+v, %v, %v", tasks, changed, err)
+	}
+	milk, mum := find(tasks, "Buy milk"), find(tasks, "Call mum")
+	if milk == nil || mum == nil || milk.NotesFile != path {
+		t.Fatalf("first sync = %+v", tasks)
+	}
+	if got := read(); strings.Contains(got, "Press 'n'") || !strings.Contains(got, "Some prose.") {
+		t.Errorf("app tasks written to the notes:\n%s", got)
+	}
+
+	// Meanwhile one box is ticked in the file and the other item deleted,
+	// though its task was renamed in the app.
+	milk.Title = "Buy oat milk"
+	write("# Today", "", "- [x] "+strings.TrimPrefix(formatMarkdownItem(*mum), "[ ] "), "Some prose.")
+	tasks, changed, err = notes.Sync(tasks, now)
+	if err != nil || !changed {
+		t.Fatalf("second sync = %v, %v", changed, err)
+	}
+	if find(tasks, "Buy oat milk") != nil || find(tasks, "Buy milk") != nil {
+		t.Errorf("task deleted in the file survived: %+v", tasks)
+	}
+	if mum := find(tasks, "Call mum"); mum == nil || !mum.Done {
+		t.Errorf("tick in the file was reverted: %+v", tasks)
+	}
+	if len(tasks) != 2 {
+		t.Errorf("tasks = %+v", tasks)
+	}
+
+	// Now the app renames the task and adds another, then deletes the first.
+	mum = find(tasks, "Call mum")
+	mum.SetTitle("Call mum back")
+	tasks = append(tasks, models.Task{ID: 9, Title: "App only"})
+	if tasks, _, err = notes.Sync(tasks, now); err != nil {
+		t.Fatal(err)
+	}
+	got := read()
+	if !strings.Contains(got, "- [x] Call mum back <!-- todo:") || strings.Contains(got, "App only") {
+		t.Errorf("after renaming in the app:\n%s", got)
+	}
+	var kept []models.Task
+	for _, task := range tasks {
+		if task.Title != "Call mum back" {
+			kept = append(kept, task)
+		}
+	}
+	if _, _, err = notes.Sync(kept, now); err != nil {
+		t.Fatal(err)
+	}
+	if got := read(); got != "# Today\n\nSome prose.\n" {
+		t.Errorf("after deleting in the app:\n%s", got)
+	}
+}
+
+func TestNotesMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.md")
+	tasks := []models.Task{{ID: 1, Title: "Buy milk"}}
+	got, changed, err := (&Notes{Path: path}).Sync(tasks, time.Now())
+	if err != nil || changed || len(got) != 1 {
+		t.Errorf("sync = %+v, %v, %v", got, changed, err)
+	}
+	if _, err := os.Stat(path); err == nil {
+		t.Error("sync created the notes file")
+	}
+}
+
+func TestNotesDeletionSurvivesRestart(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	path, state := filepath.Join(dir, "todo.md"), filepath.Join(dir, NotesState)
+	if err := os.WriteFile(path, []byte("- [ ] Buy milk\n- [ ] Call mum\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tasks, _, err := (&Notes{Path: path, State: state}).Sync(nil, now)
+	if err != nil || len(tasks) != 2 {
+		t.Fatalf("first sync = %+v, %v", tasks, err)
+	}
+
+	// The task is deleted while no app is running, say through the API,
+	// and the next run starts with a fresh Notes.
+	tasks = tasks[1:]
+	tasks, _, err = (&Notes{Path: path, State: state}).Sync(tasks, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 1 || tasks[0].Title != "Call mum" {
+		t.Errorf("deleted task came back: %+v", tasks)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "Buy milk") {
+		t.Errorf("deleted item still in the notes:\n%s", b)
+	}
+}
@@ -0,0 +1,139 @@
+package formats
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nirabyte/todo/internal/models"
+)
+
+func init() {
+	register("csv", Format{Export: exportCSV})
+	register("jsonl", Format{Export: exportJSONL})
+}
+
+// DefaultColumns are the fields CSV and JSON Lines exports can include.
+var DefaultColumns = []string{"id", "title", "done", "created", "completed", "due", "tags", "priority"}
+
+func columns(opts Options) ([]string, error) {
+	if len(opts.Columns) == 0 {
+		return DefaultColumns, nil
+	}
+	for _, c := range opts.Columns {
+		if !slices.Contains(DefaultColumns, c) {
+			return nil, fmt.Errorf("unknown column %q (have %s)", c, strings.Join(DefaultColumns, ", "))
+		}
+	}
+	return opts.Columns, nil
+}
+
+// column returns t's value for a column: a string, bool, int64 or string
+// slice, or nil for an unset time.
+func column(t models.Task, name string, loc *time.Location) any {
+	timestamp := func(at time.Time) any {
+		if at.IsZero() {
+			return nil
+		}
+		return at.In(loc).Format(time.RFC3339)
+	}
+	switch name {
+	case "id":
+		return t.ID
+	case "title":
+		return t.Title
+	case "done":
+		return t.Done
+	case "created":
+		return timestamp(t.CreatedAt)
+	case "completed":
+		return timestamp(t.CompletedAt)
+	case "due":
+		return timestamp(t.DueAt)
+	case "tags":
+		if t.Tags == nil {
+			return []string{}
+		}
+		return t.Tags
+	case "priority":
+		return t.Priority
+	}
+	return nil
+}
+
+func location(opts Options) *time.Location {
+	if opts.Location == nil {
+		return time.Local
+	}
+	return opts.Location
+}
+
+// exportCSV writes a header row and one row per task. Tags are separated
+// by spaces and unset times are empty.
+func exportCSV(w io.Writer, tasks []models.Task, opts Options) error {
+	cols, err := columns(opts)
+	if err != nil {
+		return err
+	}
+	loc := location(opts)
+	cw := csv.NewWriter(w)
+	cw.Write(cols)
+	row := make([]string, len(cols))
+	for _, t := range tasks {
+		for i, c := range cols {
+			switch v := column(t, c, loc).(type) {
+			case nil:
+				row[i] = ""
+			case string:
+				row[i] = v
+			case bool:
+				row[i] = strconv.FormatBool(v)
+			case int64:
+				row[i] = strconv.FormatInt(v, 10)
+			case []string:
+				row[i] = strings.Join(v, " ")
+			}
+		}
+		cw.Write(row)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// exportJSONL writes one JSON object per line, with keys in column order.
+func exportJSONL(w io.Writer, tasks []models.Task, opts Options) error {
+	cols, err := columns(opts)
+	if err != nil {
+		return err
+	}
+	loc := location(opts)
+	bw := bufio.NewWriter(w)
+	var line bytes.Buffer
+	for _, t := range tasks {
+		line.Reset()
+		line.WriteByte('{')
+		for i, c := range cols {
+			if i > 0 {
+				line.WriteByte(',')
+			}
+			key, _ := json.Marshal(c)
+			value, err := json.Marshal(column(t, c, loc))
+			if err != nil {
+				return err
+			}
+			line.Write(key)
+			line.WriteByte(':')
+			line.Write(value)
+		}
+		line.WriteString("}\n")
+		bw.Write(line.Bytes())
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,180 @@
+package formats
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nirabyte/todo/internal/models"
+)
+
+func init() {
+	register("todotxt", Format{Import: importTodoTxt, Export: exportTodoTxt, Update: updateCommon})
+}
+
+const (
+	todoTxtDate     = "2006-01-02"
+	todoTxtDateTime = "2006-01-02T15:04"
+)
+
+var (
+	priorityPattern = regexp.MustCompile(`^\(([A-Z])\)$`)
+	keyValuePattern = regexp.MustCompile(`^([A-Za-z][\w-]*):([^/\s]\S*)$`)
+)
+
+// todoTxtIDKey carries the task ID. Other tools use id: for their own
+// numbering, so the app's key is its own.
+const todoTxtIDKey = "todo"
+
+// importTodoTxt reads one task per line in the todo.txt format
+// (https://github.com/todotxt/todo.txt). The due and pri keys and the
+// app's own todo: key map onto Task fields. Anything else, including a due
+// date that doesn't parse, stays in the title where it was.
+func importTodoTxt(r io.Reader) ([]models.Task, error) {
+	var tasks []models.Task
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		tasks = append(tasks, parseTodoTxt(scanner.Text()))
+	}
+	return tasks, scanner.Err()
+}
+
+func parseTodoTxt(line string) models.Task {
+	var t models.Task
+	fields := strings.Fields(line)
+
+	date := func() (time.Time, bool) {
+		if len(fields) == 0 {
+			return time.Time{}, false
+		}
+		d, err := time.ParseInLocation(todoTxtDate, fields[0], time.Local)
+		if err != nil {
+			return time.Time{}, false
+		}
+		fields = fields[1:]
+		return d, true
+	}
+
+	if fields[0] == "x" {
+		t.Done = true
+		fields = fields[1:]
+		if d, ok := date(); ok {
+			t.CompletedAt = d
+			if d, ok := date(); ok {
+				t.CreatedAt = d
+			}
+		}
+	} else {
+		if m := priorityPattern.FindStringSubmatch(fields[0]); m != nil {
+			t.Priority = m[1]
+			fields = fields[1:]
+		}
+		if d, ok := date(); ok {
+			t.CreatedAt = d
+		}
+	}
+
+	// Export puts the ID last; an earlier todo: is part of the title.
+	if n := len(fields); n > 0 {
+		if m := keyValuePattern.FindStringSubmatch(fields[n-1]); m != nil && m[1] == todoTxtIDKey {
+			if id, err := strconv.ParseInt(m[2], 10, 64); err == nil && id != 0 {
+				t.ID = id
+				fields = fields[:n-1]
+			}
+		}
+	}
+	var words []string
+	for _, f := range fields {
+		if m := keyValuePattern.FindStringSubmatch(f); m == nil || !setTodoTxtKey(&t, m[1], m[2]) {
+			words = append(words, f)
+		}
+	}
+	t.SetTitle(strings.Join(words, " "))
+	return t
+}
+
+// setTodoTxtKey applies a key:value pair that maps onto a Task field and
+// reports whether it did.
+func setTodoTxtKey(t *models.Task, key, value string) bool {
+	switch key {
+	case "due":
+		due, err := parseTodoTxtDue(value)
+		if err != nil {
+			return false
+		}
+		t.DueAt = due
+		return true
+	case "pri":
+		if t.Done && t.Priority == "" && len(value) == 1 && value >= "A" && value <= "Z" {
+			t.Priority = value
+			return true
+		}
+	}
+	return false
+}
+
+func parseTodoTxtDue(s string) (time.Time, error) {
+	if due, err := time.ParseInLocation(todoTxtDateTime, s, time.Local); err == nil {
+		return due, nil
+	}
+	due, err := time.ParseInLocation(todoTxtDate, s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("bad due date %q", s)
+	}
+	return due, nil
+}
+
+// exportTodoTxt writes tasks in the todo.txt format. Each line ends with the
+// task's ID as todo:, so importing the file again updates the same tasks.
+// A done task keeps its priority as pri:, as the format suggests.
+func exportTodoTxt(w io.Writer, tasks []models.Task, _ Options) error {
+	bw := bufio.NewWriter(w)
+	for _, t := range tasks {
+		bw.WriteString(formatTodoTxt(t))
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+func formatTodoTxt(t models.Task) string {
+	var parts []string
+	if t.Done {
+		parts = append(parts, "x")
+		if !t.CompletedAt.IsZero() {
+			parts = append(parts, t.CompletedAt.Local().Format(todoTxtDate))
+			if !t.CreatedAt.IsZero() {
+				parts = append(parts, t.CreatedAt.Local().Format(todoTxtDate))
+			}
+		}
+	} else {
+		if t.Priority != "" {
+			parts = append(parts, "("+t.Priority+")")
+		}
+		if !t.CreatedAt.IsZero() {
+			parts = append(parts, t.CreatedAt.Local().Format(todoTxtDate))
+		}
+	}
+	if title := strings.Join(strings.Fields(t.Title), " "); title != "" {
+		parts = append(parts, title)
+	}
+	if !t.DueAt.IsZero() {
+		due := t.DueAt.Local()
+		if due.Hour() == 0 && due.Minute() == 0 {
+			parts = append(parts, "due:"+due.Format(todoTxtDate))
+		} else {
+			parts = append(parts, "due:"+due.Format(todoTxtDateTime))
+		}
+	}
+	if t.Done && t.Priority != "" {
+		parts = append(parts, "pri:"+t.Priority)
+	}
+	parts = append(parts, todoTxtIDKey+":"+strconv.FormatInt(t.ID, 10))
+	return strings.Join(parts, " ")
+}
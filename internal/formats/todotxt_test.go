@@ -0,0 +1,87 @@
+package formats
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nirabyte/todo/internal/models"
+)
+
+func TestTodoTxtRoundTrip(t *testing.T) {
+	in := strings.Join([]string{
+		"(A) 2026-03-01 Ask re:invoice +work @email due:2026-03-04",
+		"x 2026-03-02 2026-03-01 Read note:foo pri:B",
+		"Call mum due:soon id:3 due:2026-03-05T15:30",
+	}, "\n")
+	first, err := importTodoTxt(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := first[0].Title; got != "Ask re:invoice +work @email" {
+		t.Errorf("title = %q, want the key:value word kept in place", got)
+	}
+	if got := first[1].Title; got != "Read note:foo" || first[1].Priority != "B" {
+		t.Errorf("done task = %+v", first[1])
+	}
+	if got := first[2]; got.Title != "Call mum due:soon id:3" || got.ID != 0 || got.DueAt.IsZero() {
+		t.Errorf("task with a bad due date and a foreign id = %+v", got)
+	}
+
+	for i := range first {
+		first[i].ID = int64(i + 1)
+	}
+	var b bytes.Buffer
+	if err := exportTodoTxt(&b, first, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	second, err := importTodoTxt(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("round trip changed the tasks:\n got %+v\nwant %+v", second, first)
+	}
+}
+
+func TestTodoTxtMergeDedup(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	f := registry["todotxt"]
+	tasks := []models.Task{{ID: 3, Title: "Buy milk"}, {ID: 1772441000000000000, Title: "Call mum"}}
+
+	var b bytes.Buffer
+	if err := exportTodoTxt(&b, tasks[1:], Options{}); err != nil {
+		t.Fatal(err)
+	}
+	b.WriteString("Water plants id:3\n")
+	imported, err := importTodoTxt(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported[0].Title = "Call mum back"
+
+	merged, added, updated := f.Merge(tasks, imported, now)
+	if added != 1 || updated != 1 || len(merged) != 3 {
+		t.Fatalf("merge = %+v, added %d, updated %d", merged, added, updated)
+	}
+	if merged[0].Title != "Buy milk" {
+		t.Errorf("another tool's id:3 overwrote task 3: %+v", merged[0])
+	}
+	if merged[1].Title != "Call mum back" {
+		t.Errorf("re-import didn't update the task: %+v", merged[1])
+	}
+
+	// Exporting the merged list and importing it again adds nothing.
+	b.Reset()
+	if err := exportTodoTxt(&b, merged, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if imported, err = importTodoTxt(&b); err != nil {
+		t.Fatal(err)
+	}
+	if again, added, _ := f.Merge(merged, imported, now); added != 0 || len(again) != 3 {
+		t.Errorf("second import added copies: %+v", again)
+	}
+}
@@ -0,0 +1,103 @@
+// Package history keeps the data file in a git repository, so every save
+// is a commit that can be listed and synced between machines.
+package history
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Dir is the git directory next to the data file. It is a repository of
+// its own, so the data file never lands in a project's repository by
+// accident.
+const Dir = ".todo.git"
+
+// Repo is the history of one data file.
+type Repo struct {
+	gitDir   string
+	workTree string
+	file     string
+}
+
+// Open returns the history of the data file at path, creating the
+// repository on first use. It needs git on the PATH.
+func Open(path string) (*Repo, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, errors.New("history needs git installed")
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &Repo{
+		workTree: filepath.Dir(abs),
+		gitDir:   filepath.Join(filepath.Dir(abs), Dir),
+		file:     filepath.Base(abs),
+	}
+	if _, err := os.Stat(r.gitDir); errors.Is(err, os.ErrNotExist) {
+		if _, err := r.git("init", "-q"); err != nil {
+			return nil, err
+		}
+		// Commits must not fail for want of an identity.
+		if _, err := r.git("config", "user.email"); err != nil {
+			r.git("config", "user.name", "todo")
+			r.git("config", "user.email", "todo@localhost")
+		}
+	}
+	return r, nil
+}
+
+// git runs a git command against the repository and returns its output.
+func (r *Repo) git(args ...string) (string, error) {
+	return r.gitInput(nil, args...)
+}
+
+func (r *Repo) gitInput(stdin []byte, args ...string) (string, error) {
+	base := []string{
+		"--git-dir", r.gitDir, "--work-tree", r.workTree,
+		// Saving must never stop for a passphrase or a hook.
+		"-c", "commit.gpgsign=false", "-c", "core.hooksPath=/dev/null",
+	}
+	cmd := exec.Command("git", append(base, args...)...)
+	cmd.Dir = r.workTree
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", args[0], msg)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// rev resolves a revision, returning "" if it doesn't exist.
+func (r *Repo) rev(name string) string {
+	out, err := r.git("rev-parse", "-q", "--verify", name+"^{commit}")
+	if err != nil {
+		return ""
+	}
+	return out
+}
+
+// show returns the data file at a commit, or nil if it has none.
+func (r *Repo) show(commit string) []byte {
+	if commit == "" {
+		return nil
+	}
+	out, err := r.git("show", commit+":"+r.file)
+	if err != nil {
+		return nil
+	}
+	return []byte(out)
+}
@@ -0,0 +1,172 @@
+package history
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nirabyte/todo/internal/config"
+	"github.com/nirabyte/todo/internal/models"
+)
+
+// machine is a directory with a data file and its history, standing in
+// for one computer. Its methods chdir there, since the data file path is
+// relative.
+type machine struct {
+	t    *testing.T
+	dir  string
+	repo *Repo
+}
+
+func newMachine(t *testing.T) *machine {
+	m := &machine{t: t, dir: t.TempDir()}
+	t.Chdir(m.dir)
+	repo, err := Open(config.DataFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.repo = repo
+	return m
+}
+
+func (m *machine) save(tasks ...models.Task) {
+	m.t.Helper()
+	m.t.Chdir(m.dir)
+	data := models.AppData{Tasks: tasks}
+	if err := models.WriteData(data); err != nil {
+		m.t.Fatal(err)
+	}
+	if err := m.repo.Commit(data); err != nil {
+		m.t.Fatal(err)
+	}
+}
+
+func (m *machine) sync(remote string) string {
+	m.t.Helper()
+	m.t.Chdir(m.dir)
+	summary, err := m.repo.Sync(remote, "main")
+	if err != nil {
+		m.t.Fatal(err)
+	}
+	return summary
+}
+
+func (m *machine) tasks() map[int64]models.Task {
+	m.t.Helper()
+	m.t.Chdir(m.dir)
+	data, err := models.ReadData()
+	if err != nil {
+		m.t.Fatal(err)
+	}
+	out := map[int64]models.Task{}
+	for _, task := range data.Tasks {
+		out[task.ID] = task
+	}
+	return out
+}
+
+func (m *machine) log() string {
+	var b bytes.Buffer
+	if err := m.repo.Log(&b, 0); err != nil {
+		m.t.Fatal(err)
+	}
+	return b.String()
+}
+
+func bareRepo(t *testing.T) string {
+	dir := filepath.Join(t.TempDir(), "remote.git")
+	if out, err := exec.Command("git", "init", "-q", "--bare", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare: %v: %s", err, out)
+	}
+	return dir
+}
+
+func TestCommitOnlyWhenChanged(t *testing.T) {
+	m := newMachine(t)
+	if got := m.log(); got != "No history yet.\n" {
+		t.Errorf("empty log = %q", got)
+	}
+	m.save(models.Task{ID: 1, Title: "Buy milk"})
+	m.save(models.Task{ID: 1, Title: "Buy milk"})
+	m.save(models.Task{ID: 1, Title: "Buy milk", Done: true})
+
+	lines := strings.Split(strings.TrimSpace(m.log()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("log has %d entries, want 2:\n%s", len(lines), m.log())
+	}
+	if !strings.HasSuffix(lines[0], "done: Buy milk") || !strings.HasSuffix(lines[1], "add: Buy milk") {
+		t.Errorf("log =\n%s", m.log())
+	}
+}
+
+func TestSyncThroughBareRepo(t *testing.T) {
+	remote := bareRepo(t)
+	laptop, desktop := newMachine(t), newMachine(t)
+
+	if got := desktop.sync(remote); got != "nothing to sync" {
+		t.Errorf("syncing nothing = %q", got)
+	}
+	laptop.save(models.Task{ID: 1, Title: "Buy milk"}, models.Task{ID: 2, Title: "Call mum"})
+	if got := laptop.sync(remote); got != "pushed to a new branch main" {
+		t.Errorf("first push = %q", got)
+	}
+	if got := desktop.sync(remote); got != "pulled" {
+		t.Errorf("first pull = %q", got)
+	}
+	if len(desktop.tasks()) != 2 {
+		t.Fatalf("desktop has %+v", desktop.tasks())
+	}
+	if got := desktop.sync(remote); got != "up to date" {
+		t.Errorf("second pull = %q", got)
+	}
+
+	// Both change different tasks, then sync in turn.
+	laptop.save(models.Task{ID: 1, Title: "Buy oat milk"}, models.Task{ID: 2, Title: "Call mum"})
+	desktop.save(models.Task{ID: 1, Title: "Buy milk"}, models.Task{ID: 2, Title: "Call mum", Done: true}, models.Task{ID: 3, Title: "Water plants"})
+	if got := laptop.sync(remote); got != "pushed" {
+		t.Errorf("laptop sync = %q", got)
+	}
+	if got := desktop.sync(remote); got != "merged" {
+		t.Errorf("desktop sync = %q", got)
+	}
+	if got := laptop.sync(remote); got != "pulled" {
+		t.Errorf("laptop catching up = %q", got)
+	}
+
+	for name, m := range map[string]*machine{"laptop": laptop, "desktop": desktop} {
+		tasks := m.tasks()
+		if len(tasks) != 3 || tasks[1].Title != "Buy oat milk" || !tasks[2].Done || tasks[3].Title != "Water plants" {
+			t.Errorf("%s has %+v", name, tasks)
+		}
+	}
+	if !strings.Contains(desktop.log(), "sync: merge main") {
+		t.Errorf("no merge commit in\n%s", desktop.log())
+	}
+}
+
+func TestSyncCommitsUnsavedChanges(t *testing.T) {
+	remote := bareRepo(t)
+	laptop, desktop := newMachine(t), newMachine(t)
+
+	// A change written without a commit, as by the app with history off.
+	t.Chdir(laptop.dir)
+	if err := models.WriteData(models.AppData{Tasks: []models.Task{{ID: 1, Title: "Uncommitted"}}}); err != nil {
+		t.Fatal(err)
+	}
+	laptop.sync(remote)
+	desktop.sync(remote)
+	if got := desktop.tasks()[1].Title; got != "Uncommitted" {
+		t.Errorf("desktop got %q", got)
+	}
+}
+
+func TestSyncBadRemote(t *testing.T) {
+	m := newMachine(t)
+	m.save(models.Task{ID: 1, Title: "Buy milk"})
+	t.Chdir(m.dir)
+	if _, err := m.repo.Sync(filepath.Join(t.TempDir(), "missing.git"), "main"); err == nil {
+		t.Error("syncing with a missing remote succeeded")
+	}
+}
@@ -0,0 +1,149 @@
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// object is a JSON object with its values left undecoded, so fields this
+// build doesn't know about survive a merge.
+type object map[string]json.RawMessage
+
+// merge combines two edits of the data file made since base. Tasks are
+// matched by ID and merged field by field: a field changed on one side
+// takes that side's value, and ours wins when both changed it. A task
+// deleted on one side stays deleted unless the other side changed it.
+// A missing base merges two unrelated lists.
+func merge(base, ours, theirs []byte) ([]byte, error) {
+	var b, o, t object
+	for _, p := range []struct {
+		data []byte
+		obj  *object
+	}{{base, &b}, {ours, &o}, {theirs, &t}} {
+		*p.obj = object{}
+		if len(p.data) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(p.data, p.obj); err != nil {
+			return nil, err
+		}
+	}
+
+	baseTasks, err := tasks(b)
+	if err != nil {
+		return nil, err
+	}
+	ourTasks, err := tasks(o)
+	if err != nil {
+		return nil, err
+	}
+	theirTasks, err := tasks(t)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := mergeFields(b, o, t)
+	baseByID := index(baseTasks)
+	theirByID := index(theirTasks)
+	ourByID := index(ourTasks)
+
+	var out []object
+	for _, ot := range ourTasks {
+		id := string(ot["id"])
+		bt, inBase := baseByID[id]
+		tt, inTheirs := theirByID[id]
+		switch {
+		case inTheirs && inBase:
+			out = append(out, mergeFields(bt, ot, tt))
+		case inTheirs:
+			// Added on both sides with the same ID: an earlier sync.
+			out = append(out, mergeFields(object{}, ot, tt))
+		case inBase && equal(bt, ot):
+			// Deleted by them and untouched by us.
+		default:
+			out = append(out, ot)
+		}
+	}
+	for _, tt := range theirTasks {
+		id := string(tt["id"])
+		if _, ok := ourByID[id]; ok {
+			continue
+		}
+		if bt, inBase := baseByID[id]; inBase && equal(bt, tt) {
+			// Deleted by us and untouched by them.
+			continue
+		}
+		out = append(out, tt)
+	}
+
+	raw, err := json.Marshal(out)
+	if err != nil {
+		return nil, err
+	}
+	merged["tasks"] = raw
+	return json.Marshal(merged)
+}
+
+func tasks(o object) ([]object, error) {
+	var list []object
+	if raw, ok := o["tasks"]; ok && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+func index(list []object) map[string]object {
+	m := map[string]object{}
+	for _, o := range list {
+		m[string(o["id"])] = o
+	}
+	return m
+}
+
+// mergeFields merges the fields of one object three ways. The tasks field
+// is copied from ours and replaced by the caller.
+func mergeFields(base, ours, theirs object) object {
+	out := object{}
+	for k, v := range ours {
+		out[k] = v
+	}
+	for k, tv := range theirs {
+		ov, inOurs := ours[k]
+		bv, inBase := base[k]
+		ourChanged := inOurs != inBase || (inOurs && !same(ov, bv))
+		if !ourChanged {
+			out[k] = tv
+		}
+	}
+	// A field we left alone and they removed (omitted when empty) goes.
+	for k, bv := range base {
+		_, inTheirs := theirs[k]
+		if ov, inOurs := ours[k]; inOurs && !inTheirs && same(ov, bv) {
+			delete(out, k)
+		}
+	}
+	return out
+}
+
+func equal(a, b object) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || !same(v, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// same compares two JSON values ignoring layout.
+func same(a, b json.RawMessage) bool {
+	var ca, cb bytes.Buffer
+	if json.Compact(&ca, a) != nil || json.Compact(&cb, b) != nil {
+		return bytes.Equal(a, b)
+	}
+	return bytes.Equal(ca.Bytes(), cb.Bytes())
+}
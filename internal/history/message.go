@@ -0,0 +1,78 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/nirabyte/todo/internal/models"
+)
+
+// Message describes the change from old to new as a commit message such
+// as "done: Write report". With several changes, the first is the subject
+// and all of them are listed in the body.
+func Message(old, new models.AppData) string {
+	var changes []string
+	before := map[int64]models.Task{}
+	for _, t := range old.Tasks {
+		before[t.ID] = t
+	}
+	seen := map[int64]bool{}
+	for _, t := range new.Tasks {
+		seen[t.ID] = true
+		prev, ok := before[t.ID]
+		if !ok {
+			changes = append(changes, "add: "+t.Title)
+			continue
+		}
+		if c := taskChange(prev, t); c != "" {
+			changes = append(changes, c+": "+t.Title)
+		}
+	}
+	for _, t := range old.Tasks {
+		if !seen[t.ID] {
+			changes = append(changes, "delete: "+t.Title)
+		}
+	}
+	if old.ThemeIndex != new.ThemeIndex {
+		changes = append(changes, "theme")
+	}
+	if old.SortMode != new.SortMode {
+		changes = append(changes, "sort")
+	}
+
+	switch len(changes) {
+	case 0:
+		return "update"
+	case 1:
+		return changes[0]
+	}
+	return fmt.Sprintf("%s (+%d more)\n\n%s", changes[0], len(changes)-1, strings.Join(changes, "\n"))
+}
+
+// taskChange names what changed about a task, most telling change first,
+// or returns "" if nothing did.
+func taskChange(old, new models.Task) string {
+	switch {
+	case !old.Done && new.Done:
+		return "done"
+	case old.Done && !new.Done:
+		return "reopen"
+	case old.Title != new.Title:
+		return "edit"
+	case !old.DueAt.Equal(new.DueAt):
+		return "due"
+	case !old.SnoozedUntil.Equal(new.SnoozedUntil):
+		return "snooze"
+	case len(old.Tracked) != len(new.Tracked) || new.Tracking() != old.Tracking():
+		return "track"
+	case len(old.Sessions) != len(new.Sessions):
+		return "focus"
+	}
+	a, _ := json.Marshal(old)
+	b, _ := json.Marshal(new)
+	if string(a) != string(b) {
+		return "update"
+	}
+	return ""
+}
@@ -0,0 +1,124 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/nirabyte/todo/internal/models"
+)
+
+// Commit records the data file if it changed since the last commit. data
+// is what was just saved; it is compared with the last commit to write
+// the message.
+func (r *Repo) Commit(data models.AppData) error {
+	status, err := r.git("status", "--porcelain", "--", r.file)
+	if err != nil || status == "" {
+		return err
+	}
+	var old models.AppData
+	json.Unmarshal(r.show("HEAD"), &old)
+	if _, err := r.git("add", "--", r.file); err != nil {
+		return err
+	}
+	_, err = r.git("commit", "-q", "-m", Message(old, data), "--", r.file)
+	return err
+}
+
+// Sync commits local changes, pulls branch from remote, merges the two
+// lists if both moved on, and pushes the result back. It returns a short
+// summary of what happened.
+func (r *Repo) Sync(remote, branch string) (string, error) {
+	data, err := models.ReadData()
+	if err == nil {
+		if err := r.Commit(data); err != nil {
+			return "", err
+		}
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+
+	ref := "refs/heads/" + branch
+	if _, err := r.git("ls-remote", "--exit-code", remote, ref); err != nil {
+		if r.rev("HEAD") == "" {
+			return "nothing to sync", nil
+		}
+		if _, err := r.git("push", "-q", remote, "HEAD:"+ref); err != nil {
+			return "", err
+		}
+		return "pushed to a new branch " + branch, nil
+	}
+	if _, err := r.git("fetch", "-q", remote, ref); err != nil {
+		return "", err
+	}
+	ours, theirs := r.rev("HEAD"), r.rev("FETCH_HEAD")
+	base, _ := r.git("merge-base", ours, theirs)
+
+	var summary string
+	switch {
+	case ours == "" || base == ours:
+		if _, err := r.git("reset", "-q", "--hard", theirs); err != nil {
+			return "", err
+		}
+		summary = "pulled"
+		if ours == theirs {
+			summary = "up to date"
+		}
+	case base == theirs:
+		summary = "pushed"
+	default:
+		merged, err := merge(r.show(base), r.show(ours), r.show(theirs))
+		if err != nil {
+			return "", err
+		}
+		var data models.AppData
+		if err := json.Unmarshal(merged, &data); err != nil {
+			return "", err
+		}
+		if err := models.WriteData(data); err != nil {
+			return "", err
+		}
+		if _, err := r.git("add", "--", r.file); err != nil {
+			return "", err
+		}
+		tree, err := r.git("write-tree")
+		if err != nil {
+			return "", err
+		}
+		msg := fmt.Sprintf("sync: merge %s", branch)
+		commit, err := r.git("commit-tree", tree, "-p", ours, "-p", theirs, "-m", msg)
+		if err != nil {
+			return "", err
+		}
+		if _, err := r.git("update-ref", "HEAD", commit); err != nil {
+			return "", err
+		}
+		summary = "merged"
+	}
+
+	if summary != "up to date" && summary != "pulled" {
+		if _, err := r.git("push", "-q", remote, "HEAD:"+ref); err != nil {
+			return "", err
+		}
+	}
+	return summary, nil
+}
+
+// Log writes the last n commits, newest first, or all of them if n is
+// not positive.
+func (r *Repo) Log(w io.Writer, n int) error {
+	if r.rev("HEAD") == "" {
+		fmt.Fprintln(w, "No history yet.")
+		return nil
+	}
+	args := []string{"--git-dir", r.gitDir, "log", "--date=format:%Y-%m-%d %H:%M", "--format=%h  %ad  %s"}
+	if n > 0 {
+		args = append(args, fmt.Sprintf("-n%d", n))
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = w
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,101 @@
+package issues
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// GitHub reads issues through the GitHub REST API. BaseURL is the API
+// root, https://api.github.com unless set. WebURL is where issue pages
+// live; unless set it is BaseURL without the /api/v3 of GitHub Enterprise,
+// or https://github.com.
+type GitHub struct {
+	BaseURL string
+	WebURL  string
+	Token   string
+	HTTP    *http.Client
+}
+
+type githubIssue struct {
+	Number      int       `json:"number"`
+	Title       string    `json:"title"`
+	HTMLURL     string    `json:"html_url"`
+	State       string    `json:"state"`
+	PullRequest *struct{} `json:"pull_request"`
+}
+
+func (g *GitHub) base() string {
+	if g.BaseURL == "" {
+		return "https://api.github.com"
+	}
+	return strings.TrimSuffix(g.BaseURL, "/")
+}
+
+func (g *GitHub) header() http.Header {
+	h := http.Header{}
+	h.Set("Accept", "application/vnd.github+json")
+	if g.Token != "" {
+		h.Set("Authorization", "Bearer "+g.Token)
+	}
+	return h
+}
+
+func (g *GitHub) IssueURL(repo string, number int) string {
+	web := g.WebURL
+	if host, ok := strings.CutSuffix(g.base(), "/api/v3"); web == "" && ok {
+		web = host
+	}
+	if web == "" {
+		web = "https://github.com"
+	}
+	return fmt.Sprintf("%s/%s/issues/%d", strings.TrimSuffix(web, "/"), repo, number)
+}
+
+func (g *GitHub) List(ctx context.Context, repo, assignee string) ([]Issue, error) {
+	if assignee == "me" {
+		var user struct {
+			Login string `json:"login"`
+		}
+		if _, err := getJSON(ctx, g.HTTP, g.base()+"/user", g.header(), &user); err != nil {
+			return nil, fmt.Errorf("find out who \"me\" is: %w", err)
+		}
+		assignee = user.Login
+	}
+	// Closed issues too, so closing one shows without a request of its own.
+	q := url.Values{"state": {"all"}, "per_page": {"100"}}
+	if assignee != "" {
+		q.Set("assignee", assignee)
+	}
+	var out []Issue
+	for page := 1; ; page++ {
+		q.Set("page", fmt.Sprint(page))
+		var batch []githubIssue
+		if _, err := getJSON(ctx, g.HTTP, g.base()+"/repos/"+repo+"/issues?"+q.Encode(), g.header(), &batch); err != nil {
+			return nil, err
+		}
+		for _, is := range batch {
+			// The issues endpoint lists pull requests too.
+			if is.PullRequest == nil {
+				out = append(out, g.issue(repo, is))
+			}
+		}
+		if len(batch) < 100 {
+			return out, nil
+		}
+	}
+}
+
+func (g *GitHub) Get(ctx context.Context, repo string, number int) (Issue, error) {
+	var is githubIssue
+	_, err := getJSON(ctx, g.HTTP, fmt.Sprintf("%s/repos/%s/issues/%d", g.base(), repo, number), g.header(), &is)
+	return g.issue(repo, is), err
+}
+
+// issue uses IssueURL rather than html_url, so tasks match up even when
+// the API and web hosts are configured differently.
+func (g *GitHub) issue(repo string, is githubIssue) Issue {
+	return Issue{Number: is.Number, Title: is.Title, URL: g.IssueURL(repo, is.Number), Closed: is.State == "closed"}
+}
@@ -0,0 +1,82 @@
+package issues
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// GitLab reads issues through the GitLab REST API. BaseURL is the
+// instance, https://gitlab.com unless set.
+type GitLab struct {
+	BaseURL string
+	Token   string
+	HTTP    *http.Client
+}
+
+type gitlabIssue struct {
+	IID   int    `json:"iid"`
+	Title string `json:"title"`
+	State string `json:"state"`
+}
+
+func (g *GitLab) base() string {
+	if g.BaseURL == "" {
+		return "https://gitlab.com"
+	}
+	return strings.TrimSuffix(g.BaseURL, "/")
+}
+
+func (g *GitLab) header() http.Header {
+	h := http.Header{}
+	if g.Token != "" {
+		h.Set("PRIVATE-TOKEN", g.Token)
+	}
+	return h
+}
+
+func (g *GitLab) project(repo string) string {
+	return g.base() + "/api/v4/projects/" + url.PathEscape(repo)
+}
+
+func (g *GitLab) IssueURL(repo string, number int) string {
+	return fmt.Sprintf("%s/%s/-/issues/%d", g.base(), repo, number)
+}
+
+func (g *GitLab) List(ctx context.Context, repo, assignee string) ([]Issue, error) {
+	// Closed issues too, so closing one shows without a request of its own.
+	q := url.Values{"state": {"all"}, "per_page": {"100"}}
+	switch assignee {
+	case "":
+	case "me":
+		q.Set("scope", "assigned_to_me")
+	default:
+		q.Set("assignee_username", assignee)
+	}
+	var out []Issue
+	for page := 1; ; page++ {
+		q.Set("page", fmt.Sprint(page))
+		var batch []gitlabIssue
+		if _, err := getJSON(ctx, g.HTTP, g.project(repo)+"/issues?"+q.Encode(), g.header(), &batch); err != nil {
+			return nil, err
+		}
+		for _, is := range batch {
+			out = append(out, g.issue(repo, is))
+		}
+		if len(batch) < 100 {
+			return out, nil
+		}
+	}
+}
+
+func (g *GitLab) Get(ctx context.Context, repo string, number int) (Issue, error) {
+	var is gitlabIssue
+	_, err := getJSON(ctx, g.HTTP, fmt.Sprintf("%s/issues/%d", g.project(repo), number), g.header(), &is)
+	return g.issue(repo, is), err
+}
+
+func (g *GitLab) issue(repo string, is gitlabIssue) Issue {
+	return Issue{Number: is.IID, Title: is.Title, URL: g.IssueURL(repo, is.IID), Closed: is.State == "closed"}
+}
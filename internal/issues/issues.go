@@ -0,0 +1,158 @@
+// Package issues turns GitHub and GitLab issues into tasks.
+package issues
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nirabyte/todo/internal/models"
+)
+
+// Issue is the part of an issue a task needs.
+type Issue struct {
+	Number int
+	Title  string
+	URL    string
+	Closed bool
+}
+
+// Client reads issues from one hosting service. Assignee "me" means the
+// owner of the token.
+type Client interface {
+	List(ctx context.Context, repo, assignee string) ([]Issue, error)
+	Get(ctx context.Context, repo string, number int) (Issue, error)
+	// IssueURL is the web address of an issue, used to recognize tasks
+	// imported from repo before.
+	IssueURL(repo string, number int) string
+}
+
+// getJSON fetches url into v, sending header as authentication.
+func getJSON(ctx context.Context, client *http.Client, url string, header http.Header, v any) (http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, vs := range header {
+		req.Header[k] = vs
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, &statusError{url: url, code: resp.StatusCode, status: resp.Status, body: strings.TrimSpace(string(msg))}
+	}
+	return resp.Header, json.NewDecoder(resp.Body).Decode(v)
+}
+
+// statusError is a response other than 200 OK.
+type statusError struct {
+	url, status, body string
+	code              int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", e.url, e.status, e.body)
+}
+
+// gone reports whether err says the issue was deleted or moved away.
+func gone(err error) bool {
+	var se *statusError
+	return errors.As(err, &se) && (se.code == http.StatusNotFound || se.code == http.StatusGone)
+}
+
+// Sync brings tasks up to date with the issues assigned to assignee in
+// repo. Open issues without a task become tasks linked to the issue. Tasks
+// already linked to an issue of repo take its current title, and are done
+// exactly when it is closed. An issue that was deleted or moved to another
+// repo counts as closed.
+func Sync(ctx context.Context, c Client, repo, assignee string, tasks []models.Task, now time.Time) (merged []models.Task, added, updated int, err error) {
+	listed, err := c.List(ctx, repo, assignee)
+	if err != nil {
+		return tasks, 0, 0, err
+	}
+	byURL := map[string]Issue{}
+	for _, is := range listed {
+		byURL[is.URL] = is
+	}
+
+	prefix := strings.TrimSuffix(c.IssueURL(repo, 0), "0")
+	linked := map[string]bool{}
+	for i := range tasks {
+		t := &tasks[i]
+		number, ok := issueNumber(t.URL, prefix)
+		if !ok {
+			continue
+		}
+		linked[t.URL] = true
+		is, ok := byURL[t.URL]
+		if !ok {
+			// No longer listed, so reassigned or gone. Only an open task
+			// needs to know which; a done one stays done either way.
+			if t.Done {
+				continue
+			}
+			is, err = c.Get(ctx, repo, number)
+			if gone(err) {
+				is, err = Issue{Title: t.Title, Closed: true}, nil
+			}
+			if err != nil {
+				return tasks, added, updated, err
+			}
+		}
+		if applyIssue(t, is, now) {
+			updated++
+		}
+	}
+
+	next := now.UnixNano()
+	for _, is := range listed {
+		if linked[is.URL] || is.Closed {
+			continue
+		}
+		t := models.Task{ID: next, CreatedAt: now, URL: is.URL}
+		t.SetTitle(is.Title)
+		tasks = append(tasks, t)
+		next++
+		added++
+	}
+	return tasks, added, updated, nil
+}
+
+func issueNumber(url, prefix string) (int, bool) {
+	rest, ok := strings.CutPrefix(url, prefix)
+	if !ok {
+		return 0, false
+	}
+	var n int
+	if _, err := fmt.Sscanf(rest, "%d", &n); err != nil || fmt.Sprint(n) != rest {
+		return 0, false
+	}
+	return n, true
+}
+
+// applyIssue copies an issue's title and state onto its task and reports
+// whether anything changed.
+func applyIssue(t *models.Task, is Issue, now time.Time) bool {
+	changed := false
+	if t.Title != is.Title {
+		t.SetTitle(is.Title)
+		changed = true
+	}
+	if t.Done != is.Closed {
+		t.SetDone(is.Closed, now)
+		changed = true
+	}
+	return changed
+}
@@ -0,0 +1,295 @@
+package issues
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nirabyte/todo/internal/models"
+)
+
+// fakeIssue is an issue as a fake server keeps it.
+type fakeIssue struct {
+	title    string
+	closed   bool
+	assignee string
+	pull     bool
+}
+
+// fakeHost holds the issues of one repo, numbered from 1, and checks the
+// token on every request.
+type fakeHost struct {
+	mu     sync.Mutex
+	issues map[int]*fakeIssue
+	token  string
+	t      *testing.T
+	// gets counts the requests for single issues.
+	gets int
+}
+
+func (f *fakeHost) set(n int, is fakeIssue) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.issues[n] = &is
+}
+
+// remove deletes issue n, as an admin can on GitHub.
+func (f *fakeHost) remove(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.issues[n] = nil
+}
+
+func (f *fakeHost) requests() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.gets
+}
+
+func (f *fakeHost) update(n int, fn func(*fakeIssue)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fn(f.issues[n])
+}
+
+func (f *fakeHost) writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		f.t.Error(err)
+	}
+}
+
+func (f *fakeHost) github() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+f.token {
+			http.Error(w, `{"message":"Bad credentials"}`, http.StatusUnauthorized)
+			return
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		issue := func(n int, is *fakeIssue) map[string]any {
+			v := map[string]any{"number": n, "title": is.title, "state": "open", "html_url": "https://elsewhere.example/" + fmt.Sprint(n)}
+			if is.closed {
+				v["state"] = "closed"
+			}
+			if is.pull {
+				v["pull_request"] = map[string]any{}
+			}
+			return v
+		}
+		switch path := r.URL.Path; {
+		case path == "/user":
+			f.writeJSON(w, map[string]string{"login": "octocat"})
+		case path == "/repos/acme/app/issues":
+			var list []map[string]any
+			for n := 1; n <= len(f.issues); n++ {
+				is := f.issues[n]
+				if is == nil || is.closed && r.URL.Query().Get("state") != "all" || r.URL.Query().Get("assignee") != is.assignee {
+					continue
+				}
+				list = append(list, issue(n, is))
+			}
+			f.writeJSON(w, list)
+		case strings.HasPrefix(path, "/repos/acme/app/issues/"):
+			var n int
+			fmt.Sscanf(strings.TrimPrefix(path, "/repos/acme/app/issues/"), "%d", &n)
+			f.gets++
+			if f.issues[n] == nil {
+				http.NotFound(w, r)
+				return
+			}
+			f.writeJSON(w, issue(n, f.issues[n]))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func (f *fakeHost) gitlab() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != f.token {
+			http.Error(w, `{"message":"401 Unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		issue := func(n int, is *fakeIssue) map[string]any {
+			v := map[string]any{"iid": n, "title": is.title, "state": "opened"}
+			if is.closed {
+				v["state"] = "closed"
+			}
+			return v
+		}
+		const project = "/api/v4/projects/acme%2Fapp/issues"
+		switch path := r.URL.EscapedPath(); {
+		case path == project:
+			if r.URL.Query().Get("scope") != "assigned_to_me" {
+				http.Error(w, "want scope=assigned_to_me", http.StatusBadRequest)
+				return
+			}
+			var list []map[string]any
+			for n := 1; n <= len(f.issues); n++ {
+				is := f.issues[n]
+				// Merge requests have an API of their own.
+				if is == nil || is.closed && r.URL.Query().Get("state") != "all" || is.pull || is.assignee != "me" {
+					continue
+				}
+				list = append(list, issue(n, is))
+			}
+			f.writeJSON(w, list)
+		case strings.HasPrefix(path, project+"/"):
+			var n int
+			fmt.Sscanf(strings.TrimPrefix(path, project+"/"), "%d", &n)
+			f.gets++
+			if f.issues[n] == nil {
+				http.NotFound(w, r)
+				return
+			}
+			f.writeJSON(w, issue(n, f.issues[n]))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+// checkSync imports the assigned open issues, then changes them on the
+// server and checks that a second sync carries the changes over.
+func checkSync(t *testing.T, f *fakeHost, c Client, urlOf func(n int) string) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	mine := "octocat"
+	if _, ok := c.(*GitLab); ok {
+		mine = "me"
+	}
+	f.set(1, fakeIssue{title: "Crash on start #bug", assignee: mine})
+	f.set(2, fakeIssue{title: "Write docs", assignee: mine})
+	f.set(3, fakeIssue{title: "Someone else's", assignee: "hubot"})
+	f.set(4, fakeIssue{title: "Already closed", assignee: mine, closed: true})
+	f.set(5, fakeIssue{title: "A pull request", assignee: mine, pull: true})
+
+	own := []models.Task{{ID: 1, Title: "Unrelated"}}
+	tasks, added, updated, err := Sync(ctx, c, "acme/app", "me", own, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added != 2 || updated != 0 || len(tasks) != 3 {
+		t.Fatalf("first sync: added %d, updated %d, tasks %+v", added, updated, tasks)
+	}
+	if tasks[1].URL != urlOf(1) || tasks[1].Title != "Crash on start #bug" || len(tasks[1].Tags) != 1 {
+		t.Errorf("imported %+v", tasks[1])
+	}
+	if tasks[2].URL != urlOf(2) || tasks[2].Done {
+		t.Errorf("imported %+v", tasks[2])
+	}
+
+	// Rename one issue and close the other, which the list shows as well.
+	f.update(1, func(is *fakeIssue) { is.title = "Crash on start with no config" })
+	f.update(2, func(is *fakeIssue) { is.closed = true })
+	later := now.Add(time.Hour)
+	tasks, added, updated, err = Sync(ctx, c, "acme/app", "me", tasks, later)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added != 0 || updated != 2 || len(tasks) != 3 {
+		t.Fatalf("second sync: added %d, updated %d, tasks %+v", added, updated, tasks)
+	}
+	if n := f.requests(); n != 0 {
+		t.Errorf("second sync asked for %d issues one by one", n)
+	}
+	if tasks[1].Title != "Crash on start with no config" || len(tasks[1].Tags) != 0 {
+		t.Errorf("renamed task is %+v", tasks[1])
+	}
+	if !tasks[2].Done || !tasks[2].CompletedAt.Equal(later) {
+		t.Errorf("closed task is %+v", tasks[2])
+	}
+
+	// Reopening undoes it, and an unchanged list changes nothing.
+	f.update(2, func(is *fakeIssue) { is.closed = false })
+	tasks, _, updated, err = Sync(ctx, c, "acme/app", "me", tasks, later)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated != 1 || tasks[2].Done {
+		t.Errorf("after reopening: updated %d, task %+v", updated, tasks[2])
+	}
+	if _, _, updated, _ = Sync(ctx, c, "acme/app", "me", tasks, later); updated != 0 {
+		t.Errorf("idle sync updated %d tasks", updated)
+	}
+}
+
+func TestGitHubSync(t *testing.T) {
+	f := &fakeHost{issues: map[int]*fakeIssue{}, token: "ghp_test", t: t}
+	srv := f.github()
+	defer srv.Close()
+	c := &GitHub{BaseURL: srv.URL, WebURL: "https://github.example", Token: f.token}
+	checkSync(t, f, c, func(n int) string { return fmt.Sprintf("https://github.example/acme/app/issues/%d", n) })
+}
+
+func TestGitLabSync(t *testing.T) {
+	f := &fakeHost{issues: map[int]*fakeIssue{}, token: "glpat_test", t: t}
+	srv := f.gitlab()
+	defer srv.Close()
+	c := &GitLab{BaseURL: srv.URL, Token: f.token}
+	checkSync(t, f, c, func(n int) string { return fmt.Sprintf("%s/acme/app/-/issues/%d", srv.URL, n) })
+}
+
+func TestSyncBadToken(t *testing.T) {
+	f := &fakeHost{issues: map[int]*fakeIssue{}, token: "right", t: t}
+	srv := f.github()
+	defer srv.Close()
+	tasks := []models.Task{{ID: 1, Title: "Keep me"}}
+	got, _, _, err := Sync(context.Background(), &GitHub{BaseURL: srv.URL, Token: "wrong"}, "acme/app", "me", tasks, time.Now())
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("err = %v, want a 401", err)
+	}
+	if len(got) != 1 || got[0].Title != "Keep me" {
+		t.Errorf("tasks = %+v", got)
+	}
+}
+
+func TestGitHubEnterpriseIssueURL(t *testing.T) {
+	g := &GitHub{BaseURL: "https://git.corp.example/api/v3/"}
+	if got, want := g.IssueURL("acme/app", 7), "https://git.corp.example/acme/app/issues/7"; got != want {
+		t.Errorf("IssueURL = %q, want %q", got, want)
+	}
+}
+
+func TestSyncDeletedIssue(t *testing.T) {
+	f := &fakeHost{issues: map[int]*fakeIssue{}, token: "ghp_test", t: t}
+	srv := f.github()
+	defer srv.Close()
+	c := &GitHub{BaseURL: srv.URL, WebURL: "https://github.example", Token: f.token}
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	f.set(1, fakeIssue{title: "Crash on start", assignee: "octocat"})
+	f.set(2, fakeIssue{title: "Spam", assignee: "octocat"})
+	tasks, _, _, err := Sync(ctx, c, "acme/app", "me", nil, now)
+	if err != nil || len(tasks) != 2 {
+		t.Fatalf("first sync = %+v, %v", tasks, err)
+	}
+
+	f.remove(2)
+	f.update(1, func(is *fakeIssue) { is.title = "Crash on start, again" })
+	tasks, _, updated, err := Sync(ctx, c, "acme/app", "me", tasks, now)
+	if err != nil {
+		t.Fatalf("a deleted issue stopped the sync: %v", err)
+	}
+	if updated != 2 || tasks[0].Title != "Crash on start, again" || !tasks[1].Done || tasks[1].Title != "Spam" {
+		t.Errorf("updated %d, tasks %+v", updated, tasks)
+	}
+
+	// Its task is done now, so later syncs don't ask for it again.
+	before := f.requests()
+	if _, _, _, err := Sync(ctx, c, "acme/app", "me", tasks, now); err != nil {
+		t.Fatal(err)
+	}
+	if n := f.requests() - before; n != 0 {
+		t.Errorf("asked for %d issues again", n)
+	}
+}
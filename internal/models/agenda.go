@@ -0,0 +1,363 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/nirabyte/todo/internal/styles"
+	"github.com/nirabyte/todo/internal/themes"
+)
+
+// The agenda and the calendar show the open tasks that have a due date.
+// Like the board, they select m.Cursor, and moving a task to another day
+// keeps its time of day.
+
+// dayStart is midnight at the start of t's day.
+func dayStart(t time.Time) time.Time {
+	y, mo, d := t.Date()
+	return time.Date(y, mo, d, 0, 0, 0, 0, t.Location())
+}
+
+// scheduled lists the open tasks with a due date, soonest first.
+func (m *Model) scheduled() []int {
+	var order []int
+	for i, t := range m.Tasks {
+		if !t.Done && !t.DueAt.IsZero() {
+			order = append(order, i)
+		}
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return m.Tasks[a].DueAt.Compare(m.Tasks[b].DueAt)
+	})
+	return order
+}
+
+type agendaGroup struct {
+	title string
+	tasks []int
+}
+
+// agendaGroups sorts the scheduled tasks into Overdue, Today, Tomorrow,
+// This week (to Sunday) and Later, leaving out empty groups.
+func (m *Model) agendaGroups() []agendaGroup {
+	now := m.now().Local()
+	today := dayStart(now)
+	weekEnd := today.AddDate(0, 0, 7-(int(today.Weekday())+6)%7)
+	groups := []agendaGroup{{title: "Overdue"}, {title: "Today"}, {title: "Tomorrow"}, {title: "This week"}, {title: "Later"}}
+	for _, i := range m.scheduled() {
+		due := m.Tasks[i].DueAt
+		g := 4
+		switch {
+		case due.Before(now):
+			g = 0
+		case due.Before(today.AddDate(0, 0, 1)):
+			g = 1
+		case due.Before(today.AddDate(0, 0, 2)):
+			g = 2
+		case due.Before(weekEnd):
+			g = 3
+		}
+		groups[g].tasks = append(groups[g].tasks, i)
+	}
+	return slices.DeleteFunc(groups, func(g agendaGroup) bool { return len(g.tasks) == 0 })
+}
+
+// focusIn keeps the selection among tasks, moving it to the first of them
+// if it is elsewhere, and returns its position.
+func (m *Model) focusIn(tasks []int) int {
+	if i := slices.Index(tasks, m.Cursor); i >= 0 {
+		return i
+	}
+	if len(tasks) > 0 {
+		m.Cursor = tasks[0]
+	}
+	return 0
+}
+
+// step moves the selection delta places through tasks.
+func (m *Model) step(tasks []int, delta int) {
+	if len(tasks) > 0 {
+		m.Cursor = tasks[max(min(m.focusIn(tasks)+delta, len(tasks)-1), 0)]
+	}
+}
+
+// reschedule moves the selected task's deadline by days, if it is one of
+// the tasks on screen.
+func (m *Model) reschedule(tasks []int, days int) tea.Cmd {
+	if !slices.Contains(tasks, m.Cursor) {
+		return nil
+	}
+	t := &m.Tasks[m.Cursor]
+	t.SetDue(t.DueAt.AddDate(0, 0, days), m.now())
+	m.Save()
+	return m.scheduleTimers()
+}
+
+// openCalendar shows the month of the selected task, or of today.
+func (m *Model) openCalendar() {
+	m.State = StateCalendar
+	m.calPick = false
+	m.calDay = dayStart(m.now().Local())
+	if len(m.Tasks) > 0 && !m.Tasks[m.Cursor].DueAt.IsZero() {
+		m.calDay = dayStart(m.Tasks[m.Cursor].DueAt.Local())
+	}
+}
+
+// updateDated handles the keys shared by the agenda and the calendar.
+func (m *Model) updateDated(key string) (tea.Cmd, bool) {
+	switch key {
+	case "ctrl+c", "q":
+		m.Save()
+		return tea.Quit, true
+	case "a":
+		m.State = StateBrowse
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+func (m *Model) updateAgenda(key string) tea.Cmd {
+	if cmd, ok := m.updateDated(key); ok {
+		return cmd
+	}
+	order := m.scheduled()
+	m.focusIn(order)
+	switch key {
+	case "esc":
+		m.State = StateBrowse
+	case "tab":
+		m.openCalendar()
+	case "k", "up":
+		m.step(order, -1)
+	case "j", "down":
+		m.step(order, 1)
+	case "H":
+		return m.reschedule(order, -1)
+	case "L":
+		return m.reschedule(order, 1)
+	case "K":
+		return m.reschedule(order, -7)
+	case "J":
+		return m.reschedule(order, 7)
+	}
+	return nil
+}
+
+// dayTasks lists the scheduled tasks due on day.
+func (m *Model) dayTasks(day time.Time) []int {
+	var tasks []int
+	for _, i := range m.scheduled() {
+		if dayStart(m.Tasks[i].DueAt.Local()).Equal(day) {
+			tasks = append(tasks, i)
+		}
+	}
+	return tasks
+}
+
+// addMonths moves day by n months, staying in the last day of a shorter
+// month instead of running over into the next.
+func addMonths(day time.Time, n int) time.Time {
+	first := time.Date(day.Year(), day.Month()+time.Month(n), 1, 0, 0, 0, 0, day.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(day.Day(), last)-1)
+}
+
+func (m *Model) updateCalendar(key string) tea.Cmd {
+	if cmd, ok := m.updateDated(key); ok {
+		return cmd
+	}
+	if m.calPick {
+		tasks := m.dayTasks(m.calDay)
+		m.focusIn(tasks)
+		days := 0
+		switch key {
+		case "esc", "enter":
+			m.calPick = false
+		case "k", "up":
+			m.step(tasks, -1)
+		case "j", "down":
+			m.step(tasks, 1)
+		case "H":
+			days = -1
+		case "L":
+			days = 1
+		case "K":
+			days = -7
+		case "J":
+			days = 7
+		}
+		if days == 0 || !slices.Contains(tasks, m.Cursor) {
+			return nil
+		}
+		// The selection follows the task to its new day.
+		m.calDay = m.calDay.AddDate(0, 0, days)
+		return m.reschedule(tasks, days)
+	}
+
+	switch key {
+	case "esc":
+		m.State = StateBrowse
+	case "tab":
+		m.State = StateAgenda
+	case "h", "left":
+		m.calDay = m.calDay.AddDate(0, 0, -1)
+	case "l", "right":
+		m.calDay = m.calDay.AddDate(0, 0, 1)
+	case "k", "up":
+		m.calDay = m.calDay.AddDate(0, 0, -7)
+	case "j", "down":
+		m.calDay = m.calDay.AddDate(0, 0, 7)
+	case "[":
+		m.calDay = addMonths(m.calDay, -1)
+	case "]":
+		m.calDay = addMonths(m.calDay, 1)
+	case ".":
+		m.calDay = dayStart(m.now().Local())
+	case "enter":
+		if tasks := m.dayTasks(m.calDay); len(tasks) > 0 {
+			m.calPick = true
+			m.focusIn(tasks)
+		}
+	}
+	return nil
+}
+
+func (m *Model) viewAgenda(t themes.Theme) string {
+	groups := m.agendaGroups()
+	if len(groups) == 0 {
+		return styles.HelpStyle.Padding(2).Render("No tasks with a due date.")
+	}
+	m.focusIn(m.scheduled())
+
+	now := m.now().Local()
+	width := m.layout().width - 3
+	head := lipgloss.NewStyle().Foreground(t.Accent).Bold(true)
+	var rows []string
+	focus := 0
+	for _, g := range groups {
+		if len(rows) > 0 {
+			rows = append(rows, "")
+		}
+		rows = append(rows, head.Render(fmt.Sprintf(" %s · %d", g.title, len(g.tasks))))
+		for _, i := range g.tasks {
+			task := m.Tasks[i]
+			due := task.DueAt.Local()
+			when := due.Format("15:04")
+			if !dayStart(due).Equal(dayStart(now)) && !dayStart(due).Equal(dayStart(now).AddDate(0, 0, 1)) {
+				when = due.Format("Mon Jan 02 15:04")
+			}
+			style := styles.DueStyle
+			if due.Before(now) {
+				style = styles.OverdueStyle
+			}
+			whenCell := style.Width(17).Render(when)
+			row := lipgloss.JoinHorizontal(lipgloss.Top, whenCell, " ", ansi.Truncate(task.Title, width-lipgloss.Width(whenCell)-1, "…"))
+			row = m.zone(target{Row: i}, row)
+			if i == m.Cursor {
+				focus = len(rows)
+				rows = append(rows, styles.ListSelectedStyle.Render(row))
+			} else {
+				rows = append(rows, styles.ListItemStyle.Render(row))
+			}
+		}
+	}
+	return m.viewport(t, rows, focus)
+}
+
+func (m *Model) viewCalendar(t themes.Theme) string {
+	if m.calDay.IsZero() {
+		m.openCalendar()
+	}
+	grid := m.viewMonth(t)
+	list := m.viewDay(t)
+	if m.layout().width >= 70 {
+		return lipgloss.JoinHorizontal(lipgloss.Top, grid, "    ", list)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, grid, "", list)
+}
+
+// viewMonth draws the month of calDay, Monday first. Days with tasks due
+// are highlighted and marked with a dot.
+func (m *Model) viewMonth(t themes.Theme) string {
+	today := dayStart(m.now().Local())
+	first := time.Date(m.calDay.Year(), m.calDay.Month(), 1, 0, 0, 0, 0, m.calDay.Location())
+	due := map[int]bool{}
+	for _, i := range m.scheduled() {
+		if d := m.Tasks[i].DueAt.Local(); d.Year() == first.Year() && d.Month() == first.Month() {
+			due[d.Day()] = true
+		}
+	}
+
+	title := lipgloss.NewStyle().Foreground(t.Accent).Bold(true).Width(28).Align(lipgloss.Center).
+		Render(first.Format("January 2006"))
+	lines := []string{title, styles.HelpStyle.Render("  Mo  Tu  We  Th  Fr  Sa  Su")}
+	var week strings.Builder
+	week.WriteString(strings.Repeat("    ", (int(first.Weekday())+6)%7))
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		style := lipgloss.NewStyle().Foreground(t.Fg)
+		mark := " "
+		switch {
+		case due[day.Day()] && day.Before(today):
+			style, mark = style.Foreground(t.Warning).Bold(true), "•"
+		case due[day.Day()]:
+			style, mark = style.Foreground(t.Accent).Bold(true), "•"
+		case day.Equal(today):
+			style = style.Foreground(t.Success)
+		}
+		if day.Equal(m.calDay) {
+			style = style.Reverse(true)
+		}
+		cell := style.Render(fmt.Sprintf("%3d", day.Day())) + style.UnsetReverse().Render(mark)
+		week.WriteString(m.zone(target{Day: day.Day()}, cell))
+		if day.Weekday() == time.Sunday {
+			lines = append(lines, week.String())
+			week.Reset()
+		}
+	}
+	if week.Len() > 0 {
+		lines = append(lines, week.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// viewDay lists the tasks due on calDay.
+func (m *Model) viewDay(t themes.Theme) string {
+	tasks := m.dayTasks(m.calDay)
+	head := lipgloss.NewStyle().Foreground(t.Accent).Bold(true).
+		Render(fmt.Sprintf("%s · %d due", m.calDay.Format("Mon Jan 2"), len(tasks)))
+	lines := []string{head, ""}
+	if len(tasks) == 0 {
+		return strings.Join(append(lines, styles.HelpStyle.Render("Nothing due.")), "\n")
+	}
+	width := m.layout().width - 3
+	if m.layout().width >= 70 {
+		width -= 32 // beside the month
+	}
+	room := max(m.listHeight()-len(lines)-1, 1)
+	sel := -1
+	if m.calPick {
+		sel = m.focusIn(tasks)
+	}
+	start := max(min(sel-room+1, len(tasks)-room), 0)
+	for r := start; r < min(start+room, len(tasks)); r++ {
+		i := tasks[r]
+		row := styles.DueStyle.Render(m.Tasks[i].DueAt.Local().Format("15:04")) + " " +
+			ansi.Truncate(m.Tasks[i].Title, width-8, "…")
+		row = m.zone(target{Row: i}, row)
+		if r == sel {
+			lines = append(lines, styles.ListSelectedStyle.Render(row))
+		} else {
+			lines = append(lines, styles.ListItemStyle.Render(row))
+		}
+	}
+	if hidden := len(tasks) - min(start+room, len(tasks)); hidden > 0 {
+		lines = append(lines, styles.HelpStyle.Render(fmt.Sprintf("  ↓ %d more", hidden)))
+	}
+	return strings.Join(lines, "\n")
+}
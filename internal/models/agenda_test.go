@@ -0,0 +1,98 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAgendaGroupsWeekBoundaries(t *testing.T) {
+	at := func(day, hour int) time.Time { return time.Date(2026, 3, day, hour, 0, 0, 0, time.Local) }
+	for _, tc := range []struct {
+		now  time.Time
+		due  time.Time
+		want string
+	}{
+		// Wednesday 4 March: the week runs to the end of Sunday the 8th.
+		{at(4, 9), at(4, 8), "Overdue"},
+		{at(4, 9), at(4, 23), "Today"},
+		{at(4, 9), at(5, 0), "Tomorrow"},
+		{at(4, 9), at(6, 0), "This week"},
+		{at(4, 9), at(8, 23), "This week"},
+		{at(4, 9), at(9, 0), "Later"},
+		// On a Sunday the week has ended by tomorrow.
+		{at(8, 9), at(9, 12), "Tomorrow"},
+		{at(8, 9), at(10, 0), "Later"},
+		// On a Monday it runs all the way to the next Sunday.
+		{at(2, 9), at(8, 12), "This week"},
+	} {
+		m := &Model{Clock: fixedClock{tc.now}, Tasks: []Task{{ID: 1, DueAt: tc.due}}}
+		groups := m.agendaGroups()
+		if len(groups) != 1 || groups[0].title != tc.want {
+			t.Errorf("now %v, due %v: groups %+v, want %s", tc.now, tc.due, groups, tc.want)
+		}
+	}
+}
+
+func TestAddMonths(t *testing.T) {
+	day := func(y int, mo time.Month, d int) time.Time { return time.Date(y, mo, d, 0, 0, 0, 0, time.UTC) }
+	for _, tc := range []struct {
+		from time.Time
+		n    int
+		want time.Time
+	}{
+		{day(2026, 1, 31), 1, day(2026, 2, 28)},
+		{day(2028, 1, 31), 1, day(2028, 2, 29)},
+		{day(2026, 3, 31), -1, day(2026, 2, 28)},
+		{day(2026, 3, 31), 1, day(2026, 4, 30)},
+		{day(2026, 12, 31), 1, day(2027, 1, 31)},
+		{day(2026, 1, 31), -2, day(2025, 11, 30)},
+		{day(2026, 3, 15), 1, day(2026, 4, 15)},
+	} {
+		if got := addMonths(tc.from, tc.n); !got.Equal(tc.want) {
+			t.Errorf("addMonths(%s, %d) = %s, want %s", tc.from.Format(time.DateOnly), tc.n, got.Format(time.DateOnly), tc.want.Format(time.DateOnly))
+		}
+	}
+}
+
+func TestAgendaReschedule(t *testing.T) {
+	t.Chdir(t.TempDir())
+	now := time.Date(2026, 3, 4, 9, 0, 0, 0, time.Local)
+	due := now.Add(3 * time.Hour)
+	m := &Model{
+		Width: 80, Height: 24, Clock: fixedClock{now}, State: StateAgenda,
+		Tasks: []Task{{ID: 1, Title: "Buy milk", DueAt: due}, {ID: 2, Title: "Call mum", DueAt: due.Add(time.Hour)}},
+	}
+	m.Cursor = 1
+	m.Update(keyPress("L"))
+	m.Update(keyPress("J"))
+	if want := due.Add(time.Hour).AddDate(0, 0, 8); !m.Tasks[1].DueAt.Equal(want) {
+		t.Errorf("due = %v, want %v", m.Tasks[1].DueAt, want)
+	}
+	if !m.Tasks[0].DueAt.Equal(due) {
+		t.Errorf("the other task moved to %v", m.Tasks[0].DueAt)
+	}
+	m.Update(keyPress("K"))
+	m.Update(keyPress("H"))
+	if want := due.Add(time.Hour); !m.Tasks[1].DueAt.Equal(want) {
+		t.Errorf("due = %v after moving back, want %v", m.Tasks[1].DueAt, want)
+	}
+}
+
+func TestRescheduleSkipsHiddenTasks(t *testing.T) {
+	t.Chdir(t.TempDir())
+	now := time.Date(2026, 3, 4, 9, 0, 0, 0, time.Local)
+	due := now.Add(-time.Hour)
+	for _, state := range []AppState{StateAgenda, StateCalendar} {
+		for _, key := range []string{"H", "L", "J", "K"} {
+			// Only a done task has a due date, so nothing is on screen.
+			m := &Model{
+				Width: 80, Height: 24, Clock: fixedClock{now}, State: state, calPick: true, calDay: dayStart(due),
+				Tasks: []Task{{ID: 1, Title: "Buy milk", Done: true, DueAt: due}, {ID: 2, Title: "Call mum"}},
+			}
+			m.Update(keyPress(key))
+			if !m.Tasks[0].DueAt.Equal(due) {
+				t.Errorf("state %d, %s: done task moved to %v", state, key, m.Tasks[0].DueAt)
+			}
+		}
+	}
+}
@@ -2,7 +2,6 @@ package models
 
 import (
 	"math"
-	"math/rand"
 	"strings"
 	"time"
 
@@ -10,206 +9,258 @@ import (
 	"github.com/nirabyte/todo/internal/config"
 	"github.com/nirabyte/todo/internal/styles"
 	"github.com/nirabyte/todo/internal/themes"
+	"github.com/rivo/uniseg"
 )
 
-func renderCheckAnim(t Task, theme themes.Theme) string {
-	elapsed := time.Since(t.AnimStart).Seconds()
+// glyph is a single grapheme cluster and the number of cells it occupies.
+type glyph struct {
+	text  string
+	width int
+}
+
+func splitGlyphs(s string) []glyph {
+	var gs []glyph
+	state := -1
+	for len(s) > 0 {
+		var c string
+		var w int
+		c, s, w, state = uniseg.FirstGraphemeClusterInString(s, state)
+		gs = append(gs, glyph{c, w})
+	}
+	return gs
+}
+
+// fit pads a replacement symbol so it covers the same cells as the
+// cluster it stands in for, keeping the row width stable.
+func fit(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if w := uniseg.StringWidth(s); w < width {
+		return s + strings.Repeat(" ", width-w)
+	}
+	return s
+}
+
+// flipCase swaps the case of a cluster unless doing so would change its width.
+func flipCase(g glyph, upper bool) string {
+	s := strings.ToLower(g.text)
+	if upper {
+		s = strings.ToUpper(g.text)
+	}
+	if uniseg.StringWidth(s) != g.width {
+		return g.text
+	}
+	return s
+}
+
+// frameIndex is the animation frame shown at now. Random choices within a
+// frame are drawn from a generator seeded with it, so the same task, seed
+// and clock always produce the same frame.
+func frameIndex(start, now time.Time) int {
+	return int(now.Sub(start) * config.FPS / time.Second)
+}
+
+func renderCheckAnim(t Task, theme themes.Theme, now time.Time) string {
+	elapsed := now.Sub(t.AnimStart).Seconds()
 	total := config.CheckAnimDuration.Seconds()
 	progress := elapsed / total
 	if progress > 1.0 {
 		progress = 1.0
 	}
 
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	r := newFrameRand(t.AnimSeed, frameIndex(t.AnimStart, now))
 	text := t.Title
-	fallBack := lipgloss.NewStyle().Foreground(theme.Success).Render(text)
+	gs := splitGlyphs(text)
+
+	accent := lipgloss.NewStyle().Foreground(theme.Accent)
+	secondary := lipgloss.NewStyle().Foreground(theme.Secondary)
+	success := lipgloss.NewStyle().Foreground(theme.Success)
+	warning := lipgloss.NewStyle().Foreground(theme.Warning)
+	dim := lipgloss.NewStyle().Foreground(theme.Dim)
+	fallBack := success.Render(text)
 
 	switch t.AnimType {
 	case AnimSparkle:
 		chars := []string{"*", "+", "°", ".", "x", "o"}
 		var sb strings.Builder
-		for i := 0; i < len(text); i++ {
+		for _, g := range gs {
 			if r.Float32() < 0.4 {
 				char := chars[r.Intn(len(chars))]
-				col := theme.Accent
+				style := accent
 				if r.Intn(2) == 0 {
-					col = theme.Secondary
+					style = secondary
 				}
-				sb.WriteString(lipgloss.NewStyle().Foreground(col).Render(char))
+				sb.WriteString(style.Render(fit(char, g.width)))
 			} else {
-				sb.WriteString(lipgloss.NewStyle().Foreground(theme.Dim).Render(string(text[i])))
+				sb.WriteString(dim.Render(g.text))
 			}
 		}
 		return sb.String()
 	case AnimMatrix:
 		matrixChars := "H3LL0W0RLD$#@!%*&^"
 		var sb strings.Builder
-		for i := 0; i < len(text); i++ {
+		for _, g := range gs {
 			char := string(matrixChars[r.Intn(len(matrixChars))])
-			sb.WriteString(lipgloss.NewStyle().Foreground(theme.Success).Render(char))
+			sb.WriteString(success.Render(fit(char, g.width)))
 		}
 		return sb.String()
 	case AnimWipeRight:
-		idx := int(math.Floor(progress * float64(len(text))))
+		idx := int(math.Floor(progress * float64(len(gs))))
 		var sb strings.Builder
-		for i := 0; i < len(text); i++ {
+		for i, g := range gs {
 			if i < idx {
-				sb.WriteString(styles.StrikeStyle.Render(string(text[i])))
+				sb.WriteString(styles.StrikeStyle.Render(g.text))
 			}
 			if i == idx {
-				sb.WriteString(lipgloss.NewStyle().Background(theme.Secondary).Foreground(theme.Bg).Render(string(text[i])))
+				sb.WriteString(lipgloss.NewStyle().Background(theme.Secondary).Foreground(theme.Bg).Render(g.text))
 			}
 			if i > idx {
-				sb.WriteString(string(text[i]))
+				sb.WriteString(g.text)
 			}
 		}
 		return sb.String()
 	case AnimWipeLeft:
-		idx := len(text) - 1 - int(math.Floor(progress*float64(len(text))))
+		idx := len(gs) - 1 - int(math.Floor(progress*float64(len(gs))))
 		var sb strings.Builder
-		for i := 0; i < len(text); i++ {
+		for i, g := range gs {
 			if i > idx {
-				sb.WriteString(styles.StrikeStyle.Render(string(text[i])))
+				sb.WriteString(styles.StrikeStyle.Render(g.text))
 			}
 			if i == idx {
-				sb.WriteString(lipgloss.NewStyle().Background(theme.Accent).Foreground(theme.Bg).Render(string(text[i])))
+				sb.WriteString(lipgloss.NewStyle().Background(theme.Accent).Foreground(theme.Bg).Render(g.text))
 			}
 			if i < idx {
-				sb.WriteString(string(text[i]))
+				sb.WriteString(g.text)
 			}
 		}
 		return sb.String()
 	case AnimRainbow:
-		colors := []lipgloss.Color{theme.Accent, theme.Secondary, theme.Success, theme.Warning, "#FF0000", "#00FF00", "#0000FF"}
+		colors := []lipgloss.Style{accent, secondary, success, warning,
+			lipgloss.NewStyle().Foreground(lipgloss.Color("#FF0000")),
+			lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF00")),
+			lipgloss.NewStyle().Foreground(lipgloss.Color("#0000FF")),
+		}
 		var sb strings.Builder
-		for _, char := range text {
-			c := colors[r.Intn(len(colors))]
-			sb.WriteString(lipgloss.NewStyle().Foreground(c).Render(string(char)))
+		for _, g := range gs {
+			sb.WriteString(colors[r.Intn(len(colors))].Render(g.text))
 		}
 		return sb.String()
 	case AnimWave:
-		colors := []lipgloss.Color{theme.Accent, theme.Secondary, theme.Success, theme.Fg}
+		colors := []lipgloss.Style{accent, secondary, success, lipgloss.NewStyle().Foreground(theme.Fg)}
 		offset := int(elapsed * 30)
 		var sb strings.Builder
-		for i, char := range text {
+		for i, g := range gs {
 			cIdx := (i + offset) % len(colors)
-			sb.WriteString(lipgloss.NewStyle().Foreground(colors[cIdx]).Render(string(char)))
+			sb.WriteString(colors[cIdx].Render(g.text))
 		}
 		return sb.String()
 	case AnimBinary:
 		var sb strings.Builder
-		for i := 0; i < len(text); i++ {
+		for _, g := range gs {
 			bit := "0"
 			if r.Intn(2) == 1 {
 				bit = "1"
 			}
-			sb.WriteString(lipgloss.NewStyle().Foreground(theme.Success).Render(bit))
+			sb.WriteString(success.Render(fit(bit, g.width)))
 		}
 		return sb.String()
 	case AnimDissolve:
 		var sb strings.Builder
-		for i := 0; i < len(text); i++ {
+		for _, g := range gs {
 			if r.Float64() < progress*1.5 {
-				sb.WriteString(styles.StrikeStyle.Render(string(text[i])))
+				sb.WriteString(styles.StrikeStyle.Render(g.text))
 			} else {
-				sb.WriteString(string(text[i]))
+				sb.WriteString(g.text)
 			}
 		}
 		return sb.String()
 	case AnimFlip:
 		var sb strings.Builder
-		for _, char := range text {
-			s := string(char)
+		for _, g := range gs {
 			if r.Float32() < 0.3 {
-				if strings.ToUpper(s) == s {
-					s = strings.ToLower(s)
-				} else {
-					s = strings.ToUpper(s)
-				}
-				sb.WriteString(lipgloss.NewStyle().Foreground(theme.Secondary).Render(s))
+				s := flipCase(g, strings.ToUpper(g.text) != g.text)
+				sb.WriteString(secondary.Render(s))
 			} else {
-				sb.WriteString(s)
+				sb.WriteString(g.text)
 			}
 		}
 		return sb.String()
 	case AnimPulse:
 		var sb strings.Builder
 		phase := math.Sin(elapsed * 40)
-		col := theme.Fg
+		style := lipgloss.NewStyle().Foreground(theme.Fg)
 		if phase > 0 {
-			col = theme.Accent
+			style = accent.Bold(true)
 		}
-		for _, char := range text {
-			sb.WriteString(lipgloss.NewStyle().Foreground(col).Bold(phase > 0).Render(string(char)))
+		for _, g := range gs {
+			sb.WriteString(style.Render(g.text))
 		}
 		return sb.String()
 	case AnimTypewriter:
-		visibleChars := int(float64(len(text)) * progress)
+		visibleChars := int(float64(len(gs)) * progress)
 		var sb strings.Builder
-		for i := 0; i < len(text); i++ {
+		for i, g := range gs {
 			if i <= visibleChars {
-				sb.WriteString(lipgloss.NewStyle().Foreground(theme.Success).Render(string(text[i])))
+				sb.WriteString(success.Render(g.text))
 			} else {
-				sb.WriteString(" ")
+				sb.WriteString(fit("", g.width))
 			}
 		}
 		return sb.String()
 	case AnimParticle:
 		var sb strings.Builder
-		for i := 0; i < len(text); i++ {
+		for _, g := range gs {
 			if r.Float32() < 0.5 {
-				sb.WriteString(lipgloss.NewStyle().Foreground(theme.Secondary).Render("."))
+				sb.WriteString(secondary.Render(fit(".", g.width)))
 			} else {
-				sb.WriteString(lipgloss.NewStyle().Foreground(theme.Accent).Render(string(text[i])))
+				sb.WriteString(accent.Render(g.text))
 			}
 		}
 		return sb.String()
 	case AnimRedact:
 		var sb strings.Builder
 		chars := []string{"█", "▓", "▒", "░"}
-		for i := 0; i < len(text); i++ {
+		for _, g := range gs {
 			if r.Float32() < 0.5 {
-				char := chars[r.Intn(len(chars))]
-				sb.WriteString(lipgloss.NewStyle().Foreground(theme.Warning).Render(char))
+				char := strings.Repeat(chars[r.Intn(len(chars))], g.width)
+				sb.WriteString(warning.Render(char))
 			} else {
-				sb.WriteString(lipgloss.NewStyle().Foreground(theme.Dim).Render(string(text[i])))
+				sb.WriteString(dim.Render(g.text))
 			}
 		}
 		return sb.String()
 	case AnimChaos:
 		symbols := "!@#$%^&*()_+"
 		var sb strings.Builder
-		for i := 0; i < len(text); i++ {
+		for _, g := range gs {
 			if r.Float32() < 0.5 {
 				s := string(symbols[r.Intn(len(symbols))])
-				sb.WriteString(lipgloss.NewStyle().Foreground(theme.Secondary).Render(s))
+				sb.WriteString(secondary.Render(fit(s, g.width)))
 			} else {
-				sb.WriteString(string(text[i]))
+				sb.WriteString(g.text)
 			}
 		}
 		return sb.String()
 	case AnimConverge:
 		var sb strings.Builder
-		mid := len(text) / 2
+		mid := len(gs) / 2
 		fill := int(float64(mid) * progress)
-		for i := 0; i < len(text); i++ {
-			if i < fill || i >= len(text)-fill {
-				sb.WriteString(styles.StrikeStyle.Render(string(text[i])))
+		for i, g := range gs {
+			if i < fill || i >= len(gs)-fill {
+				sb.WriteString(styles.StrikeStyle.Render(g.text))
 			} else {
-				sb.WriteString(lipgloss.NewStyle().Foreground(theme.Accent).Render(string(text[i])))
+				sb.WriteString(accent.Render(g.text))
 			}
 		}
 		return sb.String()
 	case AnimBounce:
 		var sb strings.Builder
-		for i, char := range text {
+		for _, g := range gs {
 			if r.Intn(2) == 0 {
-				sb.WriteString(lipgloss.NewStyle().Foreground(theme.Accent).Render(string(char)))
+				sb.WriteString(accent.Render(g.text))
 			} else {
-				sb.WriteString(lipgloss.NewStyle().Foreground(theme.Secondary).Render(string(char)))
-			}
-			if i%2 == int(elapsed*10)%2 {
-				sb.WriteString("")
+				sb.WriteString(secondary.Render(g.text))
 			}
 		}
 		return sb.String()
@@ -217,100 +268,110 @@ func renderCheckAnim(t Task, theme themes.Theme) string {
 		spinners := []string{"-", "\\", "|", "/"}
 		spinIdx := int(elapsed*20) % 4
 		var sb strings.Builder
-		for range text {
-			sb.WriteString(lipgloss.NewStyle().Foreground(theme.Success).Render(spinners[spinIdx]))
+		for _, g := range gs {
+			sb.WriteString(success.Render(fit(spinners[spinIdx], g.width)))
 		}
 		return sb.String()
 	case AnimZipper:
 		var sb strings.Builder
-		mid := len(text) / 2
+		mid := len(gs) / 2
 		zipperPos := int(progress * float64(mid))
-		for i := 0; i < len(text); i++ {
+		unzipped := lipgloss.NewStyle().Background(theme.Accent).Foreground(theme.Bg)
+		for i, g := range gs {
 			distFromEdge := i
 			if i >= mid {
-				distFromEdge = len(text) - 1 - i
+				distFromEdge = len(gs) - 1 - i
 			}
 
 			if distFromEdge < zipperPos {
-				sb.WriteString(styles.StrikeStyle.Render(string(text[i])))
+				sb.WriteString(styles.StrikeStyle.Render(g.text))
 			} else {
-				sb.WriteString(lipgloss.NewStyle().Background(theme.Accent).Foreground(theme.Bg).Render(string(text[i])))
+				sb.WriteString(unzipped.Render(g.text))
 			}
 		}
 		return sb.String()
 	case AnimEraser:
 		var sb strings.Builder
-		for i := 0; i < len(text); i++ {
+		for _, g := range gs {
 			if r.Float64() < progress {
-				sb.WriteString(" ")
+				sb.WriteString(fit("", g.width))
 			} else {
-				sb.WriteString(lipgloss.NewStyle().Foreground(theme.Dim).Render(string(text[i])))
+				sb.WriteString(dim.Render(g.text))
 			}
 		}
 		return sb.String()
 	case AnimGlitch:
 		var sb strings.Builder
-		glitchChars := "¡¢£¤¥¦§¨©ª«¬®¯°±²³´µ¶·¸¹º»¼½¾¿"
-		for i := 0; i < len(text); i++ {
+		glitchChars := []rune("¡¢£¤¥¦§¨©ª«¬®¯°±²³´µ¶·¸¹º»¼½¾¿")
+		glitch := warning.Background(theme.Dim)
+		for _, g := range gs {
 			if r.Float32() < 0.3 {
 				char := string(glitchChars[r.Intn(len(glitchChars))])
-				sb.WriteString(lipgloss.NewStyle().Foreground(theme.Warning).Background(theme.Dim).Render(char))
+				sb.WriteString(glitch.Render(fit(char, g.width)))
 			} else {
-				sb.WriteString(string(text[i]))
+				sb.WriteString(g.text)
 			}
 		}
 		return sb.String()
 	case AnimMoons:
 		phases := []string{"◐", "◓", "◑", "◒"}
 		var sb strings.Builder
-		for i := 0; i < len(text); i++ {
+		for _, g := range gs {
 			if r.Float32() < 0.3 {
-				sb.WriteString(lipgloss.NewStyle().Foreground(theme.Secondary).Render(phases[r.Intn(len(phases))]))
+				sb.WriteString(secondary.Render(fit(phases[r.Intn(len(phases))], g.width)))
 			} else {
-				sb.WriteString(lipgloss.NewStyle().Foreground(theme.Dim).Render(string(text[i])))
+				sb.WriteString(dim.Render(g.text))
 			}
 		}
 		return sb.String()
 	case AnimBraille:
 		var sb strings.Builder
-		for i := 0; i < len(text); i++ {
+		for _, g := range gs {
 			if r.Float32() < 0.4 {
-				sb.WriteString(lipgloss.NewStyle().Foreground(theme.Accent).Render(string(rune(0x2800 + r.Intn(255)))))
+				sb.WriteString(accent.Render(fit(string(rune(0x2800+r.Intn(255))), g.width)))
 			} else {
-				sb.WriteString(string(text[i]))
+				sb.WriteString(g.text)
 			}
 		}
 		return sb.String()
 	case AnimHex:
 		var sb strings.Builder
 		hexChars := "0123456789ABCDEF"
-		for i := 0; i < len(text); i++ {
-			sb.WriteString(lipgloss.NewStyle().Foreground(theme.Success).Render(string(hexChars[r.Intn(len(hexChars))])))
+		for _, g := range gs {
+			sb.WriteString(success.Render(fit(string(hexChars[r.Intn(len(hexChars))]), g.width)))
 		}
 		return sb.String()
 	case AnimReverse:
 		var sb strings.Builder
-		for i := len(text) - 1; i >= 0; i-- {
-			sb.WriteString(lipgloss.NewStyle().Foreground(theme.Warning).Render(string(text[i])))
+		for i := len(gs) - 1; i >= 0; i-- {
+			sb.WriteString(warning.Render(gs[i].text))
 		}
 		return sb.String()
 	case AnimCaseFlip:
 		var sb strings.Builder
-		for _, char := range text {
-			s := string(char)
-			if r.Intn(2) == 0 {
-				s = strings.ToUpper(s)
-			} else {
-				s = strings.ToLower(s)
-			}
-			sb.WriteString(lipgloss.NewStyle().Foreground(theme.Accent).Render(s))
+		for _, g := range gs {
+			s := flipCase(g, r.Intn(2) == 0)
+			sb.WriteString(accent.Render(s))
 		}
 		return sb.String()
 	case AnimWide:
+		// Spread the letters out but stay inside the original width,
+		// pushing the tail off the end instead of growing the row.
 		var sb strings.Builder
-		for _, char := range text {
-			sb.WriteString(lipgloss.NewStyle().Foreground(theme.Secondary).Render(string(char) + " "))
+		width := uniseg.StringWidth(text)
+		used := 0
+		for _, g := range gs {
+			if used+g.width > width {
+				break
+			}
+			sb.WriteString(secondary.Render(g.text))
+			used += g.width
+			if used < width {
+				sb.WriteString(" ")
+				used++
+			}
 		}
+		sb.WriteString(strings.Repeat(" ", width-used))
 		return sb.String()
 	case AnimTraffic:
 		colors := []lipgloss.Color{theme.Warning, "#FFFF00", theme.Success}
@@ -318,35 +379,35 @@ func renderCheckAnim(t Task, theme themes.Theme) string {
 		return lipgloss.NewStyle().Foreground(colors[cIdx]).Render(text)
 	case AnimCenterStrike:
 		var sb strings.Builder
-		mid := len(text) / 2
+		mid := len(gs) / 2
 		strikeWidth := int(progress * float64(mid))
-		for i := 0; i < len(text); i++ {
+		for i, g := range gs {
 			dist := int(math.Abs(float64(i - mid)))
 			if dist < strikeWidth {
-				sb.WriteString(styles.StrikeStyle.Render(string(text[i])))
+				sb.WriteString(styles.StrikeStyle.Render(g.text))
 			} else {
-				sb.WriteString(string(text[i]))
+				sb.WriteString(g.text)
 			}
 		}
 		return sb.String()
 	case AnimLoading:
 		var sb strings.Builder
-		fill := int(progress * float64(len(text)))
-		for i := 0; i < len(text); i++ {
+		fill := int(progress * float64(len(gs)))
+		for i, g := range gs {
 			if i < fill {
-				sb.WriteString(lipgloss.NewStyle().Foreground(theme.Success).Render("█"))
+				sb.WriteString(success.Render(strings.Repeat("█", g.width)))
 			} else {
-				sb.WriteString(lipgloss.NewStyle().Foreground(theme.Dim).Render("▒"))
+				sb.WriteString(dim.Render(strings.Repeat("▒", g.width)))
 			}
 		}
 		return sb.String()
 	case AnimSlider:
 		var sb strings.Builder
-		for i := 0; i < len(text); i++ {
+		for _, g := range gs {
 			if r.Float32() < 0.3 {
-				sb.WriteString(lipgloss.NewStyle().Foreground(theme.Accent).Render("^"))
+				sb.WriteString(accent.Render(fit("^", g.width)))
 			} else {
-				sb.WriteString(string(text[i]))
+				sb.WriteString(g.text)
 			}
 		}
 		return sb.String()
@@ -355,16 +416,16 @@ func renderCheckAnim(t Task, theme themes.Theme) string {
 	return fallBack
 }
 
-func renderDeleteAnim(text string, theme themes.Theme) string {
+func renderDeleteAnim(t Task, theme themes.Theme, now time.Time) string {
 	var sb strings.Builder
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-	for i := 0; i < len(text); i++ {
+	r := newFrameRand(t.AnimSeed, frameIndex(t.AnimStart, now))
+	style := lipgloss.NewStyle().Foreground(theme.Warning).Bold(true)
+	for _, g := range splitGlyphs(t.Title) {
 		bit := "0"
 		if r.Intn(2) == 1 {
 			bit = "1"
 		}
-		sb.WriteString(lipgloss.NewStyle().Foreground(theme.Warning).Bold(true).Render(bit))
+		sb.WriteString(style.Render(fit(bit, g.width)))
 	}
 	return sb.String()
 }
-
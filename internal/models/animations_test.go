@@ -0,0 +1,73 @@
+package models
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/nirabyte/todo/internal/config"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// frame renders the whole screen with a task part way through animation
+// anim, at a fixed size, clock and seed.
+func frame(anim, width, height int, elapsed time.Duration) string {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	m := &Model{
+		Width: width, Height: height, Clock: fixedClock{start.Add(elapsed)},
+		Tasks: []Task{
+			{ID: 1, Title: "Buy oat milk 🥛 and 茶", Done: true,
+				IsAnimatingCheck: true, AnimType: anim, AnimStart: start, AnimSeed: 42},
+			{ID: 2, Title: "Call mum", DueAt: start.Add(26 * time.Hour)},
+		},
+	}
+	return m.View()
+}
+
+func TestViewGoldenFrames(t *testing.T) {
+	// Several animations only change colour, so the frames keep it.
+	profile := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	t.Cleanup(func() { lipgloss.SetColorProfile(profile) })
+
+	for anim := range AnimCount {
+		var b strings.Builder
+		for _, size := range [][2]int{{80, 12}, {30, 14}} {
+			for _, elapsed := range []time.Duration{config.CheckAnimDuration / 3, config.CheckAnimDuration * 2 / 3} {
+				out := frame(anim, size[0], size[1], elapsed)
+				if again := frame(anim, size[0], size[1], elapsed); again != out {
+					t.Errorf("animation %d: frame at %v differs between renders", anim, elapsed)
+				}
+				fmt.Fprintf(&b, "--- %dx%d at %v ---\n%s\n", size[0], size[1], elapsed, out)
+			}
+		}
+		checkGolden(t, filepath.Join("testdata", "frames", fmt.Sprintf("anim%02d.golden", anim)), b.String())
+	}
+}
+
+func checkGolden(t *testing.T, path, got string) {
+	t.Helper()
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s differs; got:\n%s", path, got)
+	}
+}
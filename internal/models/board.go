@@ -0,0 +1,306 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/nirabyte/todo/internal/styles"
+	"github.com/nirabyte/todo/internal/themes"
+)
+
+// The board shows the tasks in columns by status, tag or priority. The
+// selected card is m.Cursor, like in the list, so switching views, syncs
+// and reloads keep it; boardCol and boardRow only remember where the
+// selection was when the column or the task changes.
+const (
+	BoardByStatus   = "status"
+	BoardByTag      = "tag"
+	BoardByPriority = "priority"
+)
+
+var boardGroupings = []string{BoardByStatus, BoardByTag, BoardByPriority}
+
+type boardColumn struct {
+	title string
+	key   string // status, tag or priority the column stands for
+	tasks []int  // indexes into m.Tasks, in list order
+}
+
+// grouping is BoardColumns, defaulting to BoardByStatus.
+func (m *Model) grouping() string {
+	if slices.Contains(boardGroupings, m.BoardColumns) {
+		return m.BoardColumns
+	}
+	return BoardByStatus
+}
+
+func (m *Model) boardColumns() []boardColumn {
+	switch m.grouping() {
+	case BoardByTag:
+		return m.columnsByTag()
+	case BoardByPriority:
+		return m.columnsByPriority()
+	}
+	cols := []boardColumn{
+		{title: "Todo", key: StatusTodo},
+		{title: "Doing", key: StatusDoing},
+		{title: "Done", key: StatusDone},
+	}
+	for i, t := range m.Tasks {
+		for c := range cols {
+			if cols[c].key == t.Stage() {
+				cols[c].tasks = append(cols[c].tasks, i)
+			}
+		}
+	}
+	return cols
+}
+
+// columnsByTag has a column for each tag, after one for untagged tasks. A
+// task with several tags is in each of their columns.
+func (m *Model) columnsByTag() []boardColumn {
+	var tags []string
+	for _, t := range m.Tasks {
+		for _, tag := range t.Tags {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	slices.Sort(tags)
+	cols := []boardColumn{{title: "No tag"}}
+	for _, tag := range tags {
+		cols = append(cols, boardColumn{title: "#" + tag, key: tag})
+	}
+	for i, t := range m.Tasks {
+		if len(t.Tags) == 0 {
+			cols[0].tasks = append(cols[0].tasks, i)
+		}
+		for _, tag := range t.Tags {
+			c := slices.Index(tags, tag) + 1
+			cols[c].tasks = append(cols[c].tasks, i)
+		}
+	}
+	return cols
+}
+
+// columnsByPriority always has columns A to C, so tasks can be moved
+// there, and one for any other priority in use, then one for none.
+func (m *Model) columnsByPriority() []boardColumn {
+	prios := []string{"A", "B", "C"}
+	for _, t := range m.Tasks {
+		if t.Priority != "" && !slices.Contains(prios, t.Priority) {
+			prios = append(prios, t.Priority)
+		}
+	}
+	slices.Sort(prios)
+	var cols []boardColumn
+	for _, p := range prios {
+		cols = append(cols, boardColumn{title: "Priority " + p, key: p})
+	}
+	cols = append(cols, boardColumn{title: "No priority"})
+	for i, t := range m.Tasks {
+		c := len(cols) - 1
+		if t.Priority != "" {
+			c = slices.Index(prios, t.Priority)
+		}
+		cols[c].tasks = append(cols[c].tasks, i)
+	}
+	return cols
+}
+
+// boardFocus selects column col, on the selected task if it is in that
+// column or else on the card nearest row.
+func (m *Model) boardFocus(cols []boardColumn, col, row int) {
+	m.boardCol = max(min(col, len(cols)-1), 0)
+	tasks := cols[m.boardCol].tasks
+	if i := slices.Index(tasks, m.Cursor); i >= 0 {
+		m.boardRow = i
+		return
+	}
+	m.boardRow = max(min(row, len(tasks)-1), 0)
+	if len(tasks) > 0 {
+		m.Cursor = tasks[m.boardRow]
+	}
+}
+
+// openBoard shows the board with the selected task in its first column.
+func (m *Model) openBoard() {
+	m.State = StateBoard
+	cols := m.boardColumns()
+	for c, col := range cols {
+		if slices.Contains(col.tasks, m.Cursor) {
+			m.boardFocus(cols, c, 0)
+			return
+		}
+	}
+	m.boardFocus(cols, 0, 0)
+}
+
+func (m *Model) updateBoard(key string) tea.Cmd {
+	cols := m.boardColumns()
+	m.boardFocus(cols, m.boardCol, m.boardRow)
+	switch key {
+	case "ctrl+c", "q":
+		m.Save()
+		return tea.Quit
+	case "b", "esc":
+		m.State = StateBrowse
+	case "h", "left":
+		m.boardFocus(cols, m.boardCol-1, m.boardRow)
+	case "l", "right":
+		m.boardFocus(cols, m.boardCol+1, m.boardRow)
+	case "k", "up":
+		m.boardFocus(cols, m.boardCol, m.boardRow-1)
+	case "j", "down":
+		m.boardFocus(cols, m.boardCol, m.boardRow+1)
+	case "H", "shift+left":
+		return m.moveCard(cols, -1)
+	case "L", "shift+right":
+		return m.moveCard(cols, 1)
+	case "c":
+		i := slices.Index(boardGroupings, m.grouping())
+		m.BoardColumns = boardGroupings[(i+1)%len(boardGroupings)]
+		m.openBoard()
+	}
+	return nil
+}
+
+// moveCard moves the selected task to the column delta away, giving it
+// that column's status, tag or priority.
+func (m *Model) moveCard(cols []boardColumn, delta int) tea.Cmd {
+	from, to := m.boardCol, m.boardCol+delta
+	if to < 0 || to >= len(cols) || len(cols[from].tasks) == 0 {
+		return nil
+	}
+	t := &m.Tasks[m.Cursor]
+	id, wasDone := t.ID, t.Done
+	switch m.grouping() {
+	case BoardByTag:
+		t.SetTitle(retag(t.Title, cols[from].key, cols[to].key))
+	case BoardByPriority:
+		t.Priority = cols[to].key
+	default:
+		t.SetStatus(cols[to].key, m.now())
+	}
+	finished := t.Done && !wasDone
+
+	m.ApplySort()
+	for i := range m.Tasks {
+		if m.Tasks[i].ID == id {
+			m.Cursor = i
+		}
+	}
+	m.Save()
+	// Emptying a tag column removes it, so find the target again by key.
+	key := cols[to].key
+	cols = m.boardColumns()
+	to = slices.IndexFunc(cols, func(c boardColumn) bool { return c.key == key })
+	m.boardFocus(cols, to, m.boardRow)
+
+	var cmds []tea.Cmd
+	if finished {
+		m.celebrate()
+		if m.Celebration != nil {
+			cmds = append(cmds, m.animate())
+		}
+	}
+	return tea.Batch(append(cmds, m.scheduleTimers())...)
+}
+
+// retag swaps the #from hashtag in title for #to, which is only added if
+// the title lacks it. An empty from only adds; an empty to, the column for
+// untagged tasks, removes every hashtag. The rest of the title is left as
+// it was.
+func retag(title, from, to string) string {
+	if from == to {
+		return title
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range tagPattern.FindAllStringSubmatchIndex(title, -1) {
+		if tag := strings.ToLower(title[m[2]:m[3]]); to != "" && tag != from {
+			continue
+		}
+		start, end := m[0], m[1]
+		if start == 0 {
+			// Nothing before it to take the space with, so take the one after.
+			end = len(title) - len(strings.TrimLeftFunc(title[end:], unicode.IsSpace))
+		}
+		b.WriteString(title[last:start])
+		last = end
+	}
+	b.WriteString(title[last:])
+	title = b.String()
+	if to != "" && !slices.Contains(ParseTags(title), to) {
+		if title != "" {
+			title += " "
+		}
+		title += "#" + to
+	}
+	return title
+}
+
+func (m *Model) viewBoard(t themes.Theme) string {
+	cols := m.boardColumns()
+	m.boardFocus(cols, m.boardCol, m.boardRow)
+	width, height := m.layout().width, m.listHeight()
+
+	// Show as many columns as fit at 20 cells or more, scrolled so the
+	// selected one is among them.
+	shown := max(min(len(cols), (width+1)/21), 1)
+	first := max(min(m.boardCol-shown/2, len(cols)-shown), 0)
+	colWidth := (width - (shown - 1)) / shown
+
+	var blocks []string
+	for c := first; c < first+shown; c++ {
+		if c > first {
+			sep := strings.TrimSuffix(strings.Repeat("│\n", height), "\n")
+			blocks = append(blocks, lipgloss.NewStyle().Foreground(t.Dim).Render(sep))
+		}
+		blocks = append(blocks, m.viewBoardColumn(t, cols[c], c, colWidth, height))
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, blocks...)
+}
+
+func (m *Model) viewBoardColumn(t themes.Theme, col boardColumn, c, width, height int) string {
+	current := c == m.boardCol
+	head := lipgloss.NewStyle().Foreground(t.Dim)
+	if current {
+		head = head.Foreground(t.Accent).Bold(true)
+	}
+	lines := []string{head.Render(ansi.Truncate(fmt.Sprintf(" %s · %d", col.title, len(col.tasks)), width, "…")), ""}
+
+	// Cards are a line each; keep the selected one in view.
+	room := max(height-len(lines), 1)
+	if len(col.tasks) > room {
+		room = max(room-1, 1) // for the "more" line
+	}
+	start := 0
+	if current && m.boardRow >= room {
+		start = m.boardRow - room + 1
+	}
+	end := min(start+room, len(col.tasks))
+	for r := start; r < end; r++ {
+		i := col.tasks[r]
+		task := m.Tasks[i]
+		title := ansi.Truncate(task.Title, width-3, "…")
+		if task.Done {
+			title = styles.StrikeStyle.Render(title)
+		}
+		card := styles.ListItemStyle.Render(title)
+		if current && r == m.boardRow {
+			card = styles.ListSelectedStyle.Render(title)
+		}
+		lines = append(lines, m.zone(target{Row: i, Col: c}, card))
+	}
+	if end < len(col.tasks) {
+		lines = append(lines, styles.HelpStyle.Render(fmt.Sprintf("  ↓ %d more", len(col.tasks)-end)))
+	}
+	return lipgloss.NewStyle().Width(width).Height(height).MaxHeight(height).Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRetag(t *testing.T) {
+	for _, tc := range []struct {
+		title, from, to, want string
+	}{
+		{"Call mum #home", "home", "work", "Call mum #work"},
+		{"#home Call  mum", "home", "work", "Call  mum #work"},
+		{"Call mum #Home, soon", "home", "work", "Call mum, soon #work"},
+		{"Call mum", "", "work", "Call mum #work"},
+		{"Call mum #home #work", "home", "work", "Call mum #work"},
+		{"Call #urgent mum #home", "home", "", "Call mum"},
+		{"#home", "home", "", ""},
+		{"", "", "work", "#work"},
+		{"Call  mum\t#home", "home", "home", "Call  mum\t#home"},
+	} {
+		if got := retag(tc.title, tc.from, tc.to); got != tc.want {
+			t.Errorf("retag(%q, %q, %q) = %q, want %q", tc.title, tc.from, tc.to, got, tc.want)
+		}
+	}
+}
+
+// boardModel opens the board grouped by grouping on the task with id.
+func boardModel(t *testing.T, grouping string, id int64, tasks ...Task) *Model {
+	t.Chdir(t.TempDir())
+	for i := range tasks {
+		tasks[i].SetTitle(tasks[i].Title)
+	}
+	m := &Model{Width: 100, Height: 30, BoardColumns: grouping, Tasks: tasks}
+	for i, task := range tasks {
+		if task.ID == id {
+			m.Cursor = i
+		}
+	}
+	m.openBoard()
+	return m
+}
+
+func (m *Model) selected() Task { return m.Tasks[m.Cursor] }
+
+func (m *Model) columnTitle() string { return m.boardColumns()[m.boardCol].title }
+
+func TestMoveCardByTag(t *testing.T) {
+	m := boardModel(t, BoardByTag, 2,
+		Task{ID: 1, Title: "Water plants"},
+		Task{ID: 2, Title: "Call mum #a #z"},
+		Task{ID: 3, Title: "Pay rent #b"},
+		Task{ID: 4, Title: "Read #c"},
+	)
+	// Columns: No tag, #a, #b, #c, #z. The task is in #a, which its move
+	// to #b empties and removes.
+	if got := m.columnTitle(); got != "#a" {
+		t.Fatalf("opened on %s", got)
+	}
+	m.updateBoard("L")
+	if got := m.selected(); got.ID != 2 || got.Title != "Call mum #z #b" {
+		t.Errorf("after moving right: %+v", got)
+	}
+	if got := m.columnTitle(); got != "#b" {
+		t.Errorf("selection in %s, want #b", got)
+	}
+
+	// To the untagged column, past the one it is already in.
+	m.updateBoard("H")
+	if got := m.selected(); got.ID != 2 || got.Title != "Call mum" || len(got.Tags) != 0 {
+		t.Errorf("after moving to No tag: %+v", got)
+	}
+	if got := m.columnTitle(); got != "No tag" {
+		t.Errorf("selection in %s, want No tag", got)
+	}
+}
+
+func TestMoveCardByPriority(t *testing.T) {
+	m := boardModel(t, BoardByPriority, 2,
+		Task{ID: 1, Title: "Water plants", Priority: "A"},
+		Task{ID: 2, Title: "Call mum", Priority: "B"},
+	)
+	m.updateBoard("H")
+	if got := m.selected(); got.ID != 2 || got.Priority != "A" || m.columnTitle() != "Priority A" {
+		t.Errorf("after moving left: %+v in %s", got, m.columnTitle())
+	}
+	m.updateBoard("L")
+	m.updateBoard("L")
+	m.updateBoard("L")
+	if got := m.selected(); got.ID != 2 || got.Priority != "" || m.columnTitle() != "No priority" {
+		t.Errorf("after moving right: %+v in %s", got, m.columnTitle())
+	}
+}
+
+func TestMoveCardByStatus(t *testing.T) {
+	m := boardModel(t, BoardByStatus, 2,
+		Task{ID: 1, Title: "Water plants"},
+		Task{ID: 2, Title: "Call mum"},
+	)
+	m.updateBoard("L")
+	if got := m.selected(); got.ID != 2 || got.Stage() != StatusDoing || m.columnTitle() != "Doing" {
+		t.Errorf("after moving to Doing: %+v in %s", got, m.columnTitle())
+	}
+	m.updateBoard("L")
+	if got := m.selected(); got.ID != 2 || !got.Done || m.columnTitle() != "Done" {
+		t.Errorf("after moving to Done: %+v in %s", got, m.columnTitle())
+	}
+	data, err := ReadData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i := slices.IndexFunc(data.Tasks, func(t Task) bool { return t.ID == 2 }); i < 0 || !data.Tasks[i].Done {
+		t.Errorf("saved %+v", data.Tasks)
+	}
+}
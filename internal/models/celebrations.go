@@ -0,0 +1,265 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nirabyte/todo/internal/config"
+	"github.com/nirabyte/todo/internal/themes"
+)
+
+const (
+	CelebrateFireworks = iota
+	CelebrateConfetti
+	CelebrateBanner
+)
+
+// Celebration is a full-screen effect shown over the list for
+// config.CelebrationDuration after a milestone.
+type Celebration struct {
+	Kind    int
+	Message string
+	Start   time.Time
+	Seed    int64
+}
+
+// streakMilestones are the streak lengths, in days, worth a celebration.
+var streakMilestones = []int{3, 7, 14, 30, 50, 100, 365}
+
+// celebrate checks for a milestone after a task has been checked off.
+// Clearing the list beats reaching the daily goal, which beats a streak.
+// The goal counts once a day, even if tasks are unchecked and checked
+// again or the list cleared at the same time.
+func (m *Model) celebrate() {
+	if !m.Celebrations || m.Accessible {
+		return
+	}
+	now := m.now()
+
+	open, today := 0, 0
+	days := map[string]bool{}
+	for _, t := range m.Tasks {
+		if !t.Done {
+			open++
+			continue
+		}
+		if t.CompletedAt.IsZero() {
+			continue
+		}
+		days[dayKey(t.CompletedAt)] = true
+		if dayKey(t.CompletedAt) == dayKey(now) {
+			today++
+		}
+	}
+
+	goal := m.DailyGoal > 0 && today >= m.DailyGoal && m.GoalDay != dayKey(now)
+	if goal {
+		m.GoalDay = dayKey(now)
+	}
+
+	c := &Celebration{Start: now, Seed: m.rng().Int63()}
+	switch {
+	case open == 0 && len(m.Tasks) > 0:
+		c.Kind = CelebrateFireworks
+		c.Message = "All clear! Nothing left to do."
+	case goal:
+		c.Kind = CelebrateConfetti
+		c.Message = fmt.Sprintf("Daily goal reached: %d tasks done today!", today)
+	case today == 1 && isMilestone(streak(days, now)):
+		c.Kind = CelebrateBanner
+		c.Message = fmt.Sprintf("%d-day streak! Keep it going.", streak(days, now))
+	default:
+		return
+	}
+	m.Celebration = c
+}
+
+func dayKey(t time.Time) string {
+	return t.Local().Format("2006-01-02")
+}
+
+// streak counts consecutive days, ending today, with at least one completion.
+func streak(days map[string]bool, now time.Time) int {
+	n := 0
+	for d := now; days[dayKey(d)]; d = d.AddDate(0, 0, -1) {
+		n++
+	}
+	return n
+}
+
+func isMilestone(n int) bool {
+	for _, s := range streakMilestones {
+		if n == s {
+			return true
+		}
+	}
+	return false
+}
+
+// canvas is a grid of cells, each holding a rune and an index into the
+// palette it is rendered with. Index -1 leaves the cell blank.
+type canvas struct {
+	w, h  int
+	cells []rune
+	color []int
+}
+
+func newCanvas(w, h int) *canvas {
+	c := &canvas{w: w, h: h, cells: make([]rune, w*h), color: make([]int, w*h)}
+	for i := range c.cells {
+		c.cells[i] = ' '
+		c.color[i] = -1
+	}
+	return c
+}
+
+func (c *canvas) set(x, y int, r rune, color int) {
+	if x < 0 || y < 0 || x >= c.w || y >= c.h {
+		return
+	}
+	c.cells[y*c.w+x] = r
+	c.color[y*c.w+x] = color
+}
+
+func (c *canvas) text(x, y int, s string, color int) {
+	for i, r := range []rune(s) {
+		c.set(x+i, y, r, color)
+	}
+}
+
+func (c *canvas) render(palette []lipgloss.Style) string {
+	var sb strings.Builder
+	for y := 0; y < c.h; y++ {
+		row := y * c.w
+		for x := 0; x < c.w; {
+			col := c.color[row+x]
+			end := x
+			for end < c.w && c.color[row+end] == col {
+				end++
+			}
+			run := string(c.cells[row+x : row+end])
+			if col < 0 {
+				sb.WriteString(run)
+			} else {
+				sb.WriteString(palette[col].Render(run))
+			}
+			x = end
+		}
+		if y < c.h-1 {
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+func (m *Model) viewCelebration(theme themes.Theme) string {
+	c := m.Celebration
+	w, h := m.Width, m.Height
+	if w <= 0 || h <= 0 {
+		return ""
+	}
+	now := m.now()
+	progress := float64(now.Sub(c.Start)) / float64(config.CelebrationDuration)
+	progress = math.Max(0, math.Min(progress, 1))
+
+	palette := []lipgloss.Style{
+		lipgloss.NewStyle().Foreground(theme.Accent),
+		lipgloss.NewStyle().Foreground(theme.Secondary),
+		lipgloss.NewStyle().Foreground(theme.Success),
+		lipgloss.NewStyle().Foreground(theme.Warning),
+		lipgloss.NewStyle().Foreground(theme.Fg),
+		lipgloss.NewStyle().Foreground(theme.Bg).Background(theme.Accent).Bold(true),
+	}
+	colors := len(palette) - 1
+	bannerColor := len(palette) - 1
+
+	cv := newCanvas(w, h)
+	switch c.Kind {
+	case CelebrateConfetti:
+		drawConfetti(cv, c.Seed, progress, colors)
+	case CelebrateFireworks:
+		drawFireworks(cv, c.Seed, progress, colors)
+	case CelebrateBanner:
+		drawStars(cv, c.Seed, frameIndex(c.Start, now), colors)
+	}
+	drawBanner(cv, c.Message, bannerColor)
+	return cv.render(palette)
+}
+
+func drawConfetti(cv *canvas, seed int64, progress float64, colors int) {
+	r := newFrameRand(seed, 0)
+	glyphs := []rune("*+o.x~")
+	w, h := float64(cv.w), float64(cv.h)
+	for i := 0; i < cv.w*cv.h/10; i++ {
+		x0 := r.Float64() * w
+		y0 := -r.Float64() * h
+		speed := 1 + r.Float64()
+		phase := r.Float64() * 2 * math.Pi
+		g := glyphs[r.Intn(len(glyphs))]
+		col := r.Intn(colors)
+
+		y := y0 + speed*progress*2*h
+		x := x0 + 2*math.Sin(phase+progress*12)
+		cv.set(int(x), int(y), g, col)
+	}
+}
+
+func drawFireworks(cv *canvas, seed int64, progress float64, colors int) {
+	r := newFrameRand(seed, 0)
+	w, h := float64(cv.w), float64(cv.h)
+	maxR := math.Min(w/2, h) / 2.5
+	const bursts, points, life = 6, 20, 0.45
+	for b := 0; b < bursts; b++ {
+		cx := w * (0.1 + 0.8*r.Float64())
+		cy := h * (0.15 + 0.5*r.Float64())
+		col := r.Intn(colors)
+		age := (progress - float64(b)*0.1) / life
+		if age < 0 || age > 1 {
+			continue
+		}
+		glyph := '*'
+		if age > 0.5 {
+			glyph = '+'
+		}
+		if age > 0.8 {
+			glyph = '.'
+		}
+		radius := age * maxR
+		for k := 0; k < points; k++ {
+			a := 2 * math.Pi * float64(k) / points
+			cv.set(int(cx+math.Cos(a)*radius*2), int(cy+math.Sin(a)*radius), glyph, col)
+		}
+		cv.set(int(cx), int(cy), '.', col)
+	}
+}
+
+func drawStars(cv *canvas, seed int64, frame int, colors int) {
+	// Twinkle at a few frames per second rather than every tick.
+	r := newFrameRand(seed, frame/6)
+	for i := 0; i < cv.w*cv.h/25; i++ {
+		cv.set(r.Intn(cv.w), r.Intn(cv.h), []rune("*.+")[r.Intn(3)], r.Intn(colors))
+	}
+}
+
+// drawBanner boxes msg in the middle of cv, cutting it to fit, and skips
+// the box when there is no room for any of it.
+func drawBanner(cv *canvas, msg string, color int) {
+	if cv.w < 7 || cv.h < 3 {
+		return
+	}
+	inner := len([]rune(msg)) + 4
+	if inner+2 > cv.w {
+		inner = cv.w - 2
+		msg = string([]rune(msg)[:inner-4])
+	}
+	x := (cv.w - inner - 2) / 2
+	y := cv.h/2 - 1
+	pad := strings.Repeat(" ", inner)
+	cv.text(x, y, "╭"+strings.Repeat("─", inner)+"╮", color)
+	cv.text(x, y+1, "│"+pad+"│", color)
+	cv.text(x+3, y+1, msg, color)
+	cv.text(x, y+2, "╰"+strings.Repeat("─", inner)+"╯", color)
+}
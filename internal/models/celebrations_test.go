@@ -0,0 +1,112 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nirabyte/todo/internal/themes"
+)
+
+func TestViewCelebrationNarrow(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	for w := 1; w <= 12; w++ {
+		for h := 1; h <= 4; h++ {
+			m := &Model{Width: w, Height: h, Clock: fixedClock{now}, Celebration: &Celebration{
+				Kind: CelebrateBanner, Message: "All clear!", Start: now, Seed: 1,
+			}}
+			out := m.viewCelebration(themes.All[0])
+			if got := lipgloss.Width(out); got != w {
+				t.Errorf("%dx%d: width %d", w, h, got)
+			}
+		}
+	}
+}
+
+func TestKeyDismissingCelebrationIsSwallowed(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	m := &Model{
+		Width: 80, Height: 24, Clock: fixedClock{now},
+		Tasks:       []Task{{ID: 1, Title: "one"}, {ID: 2, Title: "two"}},
+		Celebration: &Celebration{Kind: CelebrateConfetti, Start: now},
+	}
+	m.Update(keyPress("j"))
+	if m.Celebration != nil {
+		t.Fatal("celebration still showing")
+	}
+	if m.Cursor != 0 {
+		t.Fatalf("cursor moved to %d by the dismissing key", m.Cursor)
+	}
+	m.Update(keyPress("j"))
+	if m.Cursor != 1 {
+		t.Fatalf("cursor at %d after the next key", m.Cursor)
+	}
+}
+
+func TestDailyGoalCelebratedOncePerDay(t *testing.T) {
+	t.Chdir(t.TempDir())
+	now := time.Date(2026, 3, 2, 15, 0, 0, 0, time.Local)
+	m := &Model{
+		Clock: fixedClock{now}, Celebrations: true, DailyGoal: 2,
+		Tasks: []Task{
+			{ID: 1, Title: "one", Done: true, CompletedAt: now},
+			{ID: 2, Title: "two", Done: true, CompletedAt: now},
+			{ID: 3, Title: "three"},
+			{ID: 4, Title: "four"},
+		},
+	}
+	check := func(want bool, when string) {
+		t.Helper()
+		m.Celebration = nil
+		m.celebrate()
+		if got := m.Celebration != nil && m.Celebration.Kind == CelebrateConfetti; got != want {
+			t.Errorf("%s: celebration %+v, want confetti %v", when, m.Celebration, want)
+		}
+	}
+	check(true, "goal reached")
+	// Unchecking a task and checking it again doesn't count twice, nor
+	// does going past the goal.
+	check(false, "checked again")
+	m.Tasks[2].Done, m.Tasks[2].CompletedAt = true, now
+	check(false, "past the goal")
+
+	// The day is kept with the list, so a restart doesn't repeat it.
+	m.Save()
+	data, err := ReadData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m = &Model{Clock: fixedClock{now}, Celebrations: true, DailyGoal: 2, GoalDay: data.GoalDay, Tasks: data.Tasks}
+	check(false, "after a restart")
+
+	// Tomorrow counts afresh.
+	tomorrow := now.AddDate(0, 0, 1)
+	m.Clock = fixedClock{tomorrow}
+	m.Tasks[0].CompletedAt, m.Tasks[1].CompletedAt = tomorrow, tomorrow
+	check(true, "the next day")
+}
+
+func TestDailyGoalReachedWithAllClear(t *testing.T) {
+	now := time.Date(2026, 3, 2, 15, 0, 0, 0, time.Local)
+	m := &Model{
+		Clock: fixedClock{now}, Celebrations: true, DailyGoal: 1,
+		Tasks: []Task{{ID: 1, Title: "one", Done: true, CompletedAt: now}},
+	}
+	m.celebrate()
+	if m.Celebration == nil || m.Celebration.Kind != CelebrateFireworks {
+		t.Fatalf("celebration %+v, want fireworks", m.Celebration)
+	}
+	// The goal was met along with clearing the list, so a task added and
+	// done later the same day is no new milestone.
+	m.Celebration = nil
+	m.Tasks = append(m.Tasks, Task{ID: 2, Title: "two", Done: true, CompletedAt: now}, Task{ID: 3, Title: "three"})
+	m.celebrate()
+	if m.Celebration != nil {
+		t.Errorf("celebration %+v", m.Celebration)
+	}
+}
+
+// fixedClock is a Clock stopped at a moment; tests move it by hand.
+type fixedClock struct{ t time.Time }
+
+func (c fixedClock) Now() time.Time { return c.t }
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Clock reports the current time. The app runs on the wall clock; tests
+// and replays can swap in a fixed one.
+type Clock interface {
+	Now() time.Time
+}
+
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time { return time.Now() }
+
+func (m *Model) now() time.Time {
+	if m.Clock == nil {
+		return time.Now()
+	}
+	return m.Clock.Now()
+}
+
+func (m *Model) rng() *rand.Rand {
+	if m.Rand == nil {
+		m.Rand = rand.New(rand.NewSource(m.now().UnixNano()))
+	}
+	return m.Rand
+}
+
+// frameRand is a small splitmix64 generator. Animation frames seed one on
+// the stack from the task's seed and the frame number, so a frame is a pure
+// function of its inputs and rendering it allocates no generator.
+type frameRand struct {
+	state uint64
+}
+
+func newFrameRand(seed int64, frame int) frameRand {
+	return frameRand{state: uint64(seed) ^ uint64(frame)*0x9e3779b97f4a7c15}
+}
+
+func (r *frameRand) next() uint64 {
+	r.state += 0x9e3779b97f4a7c15
+	z := r.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+func (r *frameRand) Intn(n int) int {
+	return int(r.next() % uint64(n))
+}
+
+func (r *frameRand) Float64() float64 {
+	return float64(r.next()>>11) / (1 << 53)
+}
+
+func (r *frameRand) Float32() float32 {
+	return float32(r.next()>>40) / (1 << 24)
+}
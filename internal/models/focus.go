@@ -0,0 +1,265 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nirabyte/todo/internal/notify"
+	"github.com/nirabyte/todo/internal/styles"
+	"github.com/nirabyte/todo/internal/themes"
+)
+
+const (
+	PhaseWork = iota
+	PhaseShortBreak
+	PhaseLongBreak
+)
+
+// Interval is a span of time spent on a task.
+type Interval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end,omitzero"`
+}
+
+func (i Interval) Duration() time.Duration {
+	return i.End.Sub(i.Start)
+}
+
+// FocusSettings are the pomodoro phase lengths.
+type FocusSettings struct {
+	Work           time.Duration
+	ShortBreak     time.Duration
+	LongBreak      time.Duration
+	LongBreakEvery int
+}
+
+// Focus is a running pomodoro bound to one task. Completed work phases
+// are logged to the task's Sessions.
+type Focus struct {
+	TaskID     int64
+	Phase      int
+	PhaseStart time.Time
+	PhaseEnd   time.Time
+	Completed  int
+
+	// Remaining is the time left in the phase while paused.
+	Paused    bool
+	Remaining time.Duration
+}
+
+func (m *Model) focusTask() *Task {
+	if m.Focus == nil {
+		return nil
+	}
+	for i := range m.Tasks {
+		if m.Tasks[i].ID == m.Focus.TaskID {
+			return &m.Tasks[i]
+		}
+	}
+	return nil
+}
+
+func (m *Model) phaseLength(phase int) time.Duration {
+	switch phase {
+	case PhaseShortBreak:
+		return m.FocusSettings.ShortBreak
+	case PhaseLongBreak:
+		return m.FocusSettings.LongBreak
+	}
+	return m.FocusSettings.Work
+}
+
+func (m *Model) startFocus(t Task) {
+	now := m.now()
+	m.Focus = &Focus{
+		TaskID:     t.ID,
+		Phase:      PhaseWork,
+		PhaseStart: now,
+		PhaseEnd:   now.Add(m.phaseLength(PhaseWork)),
+	}
+	m.State = StateFocus
+}
+
+func (m *Model) stopFocus() {
+	m.Focus = nil
+	m.State = StateBrowse
+}
+
+func (m *Model) toggleFocusPause() {
+	f := m.Focus
+	now := m.now()
+	if f.Paused {
+		f.PhaseEnd = now.Add(f.Remaining)
+		f.Paused = false
+		return
+	}
+	f.Remaining = f.PhaseEnd.Sub(now)
+	f.Paused = true
+}
+
+// advanceFocus moves to the next phase once the current one has run out,
+// logging finished work phases and announcing each change.
+func (m *Model) advanceFocus(skip bool) {
+	f := m.Focus
+	now := m.now()
+	if f == nil || f.Paused || (!skip && now.Before(f.PhaseEnd)) {
+		return
+	}
+
+	task := m.focusTask()
+	title := ""
+	if task != nil {
+		title = task.Title
+	}
+	var body string
+	if f.Phase == PhaseWork {
+		if !skip && task != nil {
+			task.Sessions = append(task.Sessions, Interval{Start: f.PhaseStart, End: f.PhaseEnd})
+			f.Completed++
+			m.Save()
+		}
+		f.Phase = PhaseShortBreak
+		if every := m.FocusSettings.LongBreakEvery; every > 0 && f.Completed > 0 && f.Completed%every == 0 {
+			f.Phase = PhaseLongBreak
+		}
+		body = fmt.Sprintf("Focus session done: %s. Take a break.", title)
+	} else {
+		f.Phase = PhaseWork
+		body = fmt.Sprintf("Break over. Back to: %s", title)
+	}
+	f.PhaseStart = now
+	f.PhaseEnd = now.Add(m.phaseLength(f.Phase))
+
+	if !skip {
+		n := notify.Notification{Title: "Focus", Body: body}
+		if task != nil {
+			n.TaskID, n.Tags, n.Sinks = task.ID, task.Tags, task.Notify
+		}
+		notifier := m.notifier()
+		go func() { m.Report("Focus alert", notifier.Notify(n)) }()
+	}
+}
+
+// focusTotal sums the logged sessions of tasks, limited to those started
+// on day when day is not zero.
+func focusTotal(tasks []Task, day time.Time) (time.Duration, int) {
+	var total time.Duration
+	count := 0
+	for _, t := range tasks {
+		for _, s := range t.Sessions {
+			if !day.IsZero() && dayKey(s.Start) != dayKey(day) {
+				continue
+			}
+			total += s.Duration()
+			count++
+		}
+	}
+	return total, count
+}
+
+var bigDigits = map[rune][5]string{
+	'0': {"███", "█ █", "█ █", "█ █", "███"},
+	'1': {"  █", "  █", "  █", "  █", "  █"},
+	'2': {"███", "  █", "███", "█  ", "███"},
+	'3': {"███", "  █", "███", "  █", "███"},
+	'4': {"█ █", "█ █", "███", "  █", "  █"},
+	'5': {"███", "█  ", "███", "  █", "███"},
+	'6': {"███", "█  ", "███", "█ █", "███"},
+	'7': {"███", "  █", "  █", "  █", "  █"},
+	'8': {"███", "█ █", "███", "█ █", "███"},
+	'9': {"███", "█ █", "███", "  █", "███"},
+	':': {" ", "█", " ", "█", " "},
+}
+
+// bigClock renders "MM:SS" five rows tall.
+func bigClock(s string) string {
+	var rows [5]strings.Builder
+	for i, r := range s {
+		glyph, ok := bigDigits[r]
+		if !ok {
+			continue
+		}
+		for row := range rows {
+			if i > 0 {
+				rows[row].WriteString(" ")
+			}
+			rows[row].WriteString(glyph[row])
+		}
+	}
+	lines := make([]string, len(rows))
+	for i := range rows {
+		lines[i] = rows[i].String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+func clockString(d time.Duration) string {
+	d = max(d, 0).Round(time.Second)
+	return fmt.Sprintf("%02d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+func (m *Model) viewFocus(theme themes.Theme) string {
+	f := m.Focus
+	task := m.focusTask()
+	now := m.now()
+
+	remaining := f.PhaseEnd.Sub(now)
+	if f.Paused {
+		remaining = f.Remaining
+	}
+
+	label, color := "FOCUS", theme.Accent
+	switch f.Phase {
+	case PhaseShortBreak:
+		label, color = "SHORT BREAK", theme.Success
+	case PhaseLongBreak:
+		label, color = "LONG BREAK", theme.Success
+	}
+	if f.Paused {
+		label += " (paused)"
+	}
+
+	title := ""
+	var taskTotal time.Duration
+	var taskCount int
+	if task != nil {
+		title = task.Title
+		taskTotal, taskCount = focusTotal([]Task{*task}, time.Time{})
+	}
+	dayTotal, dayCount := focusTotal(m.Tasks, now)
+
+	length := m.phaseLength(f.Phase)
+	barWidth := 30
+	filled := 0
+	if length > 0 {
+		filled = int(float64(barWidth) * float64(length-remaining) / float64(length))
+		filled = max(0, min(filled, barWidth))
+	}
+	bar := lipgloss.NewStyle().Foreground(color).Render(strings.Repeat("█", filled)) +
+		lipgloss.NewStyle().Foreground(theme.Dim).Render(strings.Repeat("░", barWidth-filled))
+
+	body := lipgloss.JoinVertical(lipgloss.Center,
+		lipgloss.NewStyle().Foreground(color).Bold(true).Render(label),
+		"",
+		lipgloss.NewStyle().Foreground(color).Render(bigClock(clockString(remaining))),
+		"",
+		bar,
+		"",
+		lipgloss.NewStyle().Foreground(theme.Fg).Render(title),
+		styles.HelpStyle.Render(fmt.Sprintf("This task: %d sessions, %s • Today: %d sessions, %s",
+			taskCount, shortDur(taskTotal), dayCount, shortDur(dayTotal))),
+	)
+
+	container := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Accent).
+		Padding(1, 4).
+		Render(body)
+	header := styles.HeaderStyle.Render("// FOCUS")
+	help := styles.HelpStyle.Render("Pause (Space) • Skip phase (n) • Exit (Esc)")
+
+	ui := lipgloss.JoinVertical(lipgloss.Center, header, container, help)
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, ui)
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/nirabyte/todo/internal/styles"
+)
+
+// The list adapts to the terminal: below compactBelow columns it drops the
+// row numbers and shortens timers, and from wideFrom on it grows and adds
+// columns for priority, due date and tags.
+const (
+	compactBelow = 60
+	wideFrom     = 120
+)
+
+type layout struct {
+	width   int // inside the list border
+	compact bool
+	wide    bool
+}
+
+func (m *Model) layout() layout {
+	l := layout{compact: m.Width < compactBelow, wide: m.Width >= wideFrom}
+	l.width = min(m.Width-4, 100)
+	if l.wide {
+		l.width = min(m.Width-4, 140)
+	}
+	l.width = max(l.width, 20)
+	return l
+}
+
+// hangingIndent wraps title to width, indenting the lines after the first
+// so that a wrapped title reads as one entry.
+func hangingIndent(title string, width int) string {
+	lines := strings.Split(lipgloss.NewStyle().Width(width-2).Render(title), "\n")
+	for i := 1; i < len(lines); i++ {
+		lines[i] = "  " + lines[i]
+	}
+	return lipgloss.NewStyle().Width(width).Render(strings.Join(lines, "\n"))
+}
+
+// wideColumnsWidth is what viewColumns takes up, gaps included.
+const wideColumnsWidth = 2 + 3 + 2 + 12 + 2 + 14
+
+// viewColumns is the priority, due date and tags of a row in wide mode.
+func viewColumns(t Task) string {
+	cell := func(s string, width int) string {
+		return lipgloss.NewStyle().Width(width).MaxWidth(width).Render(ansi.Truncate(s, width, "…"))
+	}
+	prio, due := "", ""
+	if t.Priority != "" {
+		prio = "(" + t.Priority + ")"
+	}
+	if !t.DueAt.IsZero() {
+		due = t.DueAt.Local().Format("Jan 02 15:04")
+	}
+	tags := make([]string, len(t.Tags))
+	for i, tag := range t.Tags {
+		tags[i] = "#" + tag
+	}
+	return styles.HelpStyle.Render(lipgloss.JoinHorizontal(lipgloss.Top,
+		"  ", cell(prio, 3),
+		"  ", cell(due, 12),
+		"  ", cell(strings.Join(tags, " "), 14),
+	))
+}
+
+// compactDur is shortDur cut down to its largest unit.
+func compactDur(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%ds", int(d.Seconds()))
+}
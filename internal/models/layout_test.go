@@ -0,0 +1,97 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/nirabyte/todo/internal/themes"
+)
+
+func TestLayoutThresholds(t *testing.T) {
+	for _, tc := range []struct {
+		width int
+		want  layout
+	}{
+		{10, layout{width: 20, compact: true}},
+		{59, layout{width: 55, compact: true}},
+		{60, layout{width: 56}},
+		{104, layout{width: 100}},
+		{119, layout{width: 100}},
+		{120, layout{width: 116, wide: true}},
+		{200, layout{width: 140, wide: true}},
+	} {
+		if got := (&Model{Width: tc.width}).layout(); got != tc.want {
+			t.Errorf("layout at %d columns = %+v, want %+v", tc.width, got, tc.want)
+		}
+	}
+}
+
+func TestHangingIndent(t *testing.T) {
+	got := hangingIndent("Buy oat milk and call mum about the weekend", 20)
+	lines := strings.Split(got, "\n")
+	if len(lines) < 2 {
+		t.Fatalf("title not wrapped:\n%s", got)
+	}
+	if strings.HasPrefix(lines[0], " ") {
+		t.Errorf("first line indented: %q", lines[0])
+	}
+	for i, l := range lines {
+		if w := lipgloss.Width(l); w != 20 {
+			t.Errorf("line %d is %d wide: %q", i, w, l)
+		}
+		if i > 0 && (!strings.HasPrefix(l, "  ") || strings.HasPrefix(l, "   ")) {
+			t.Errorf("line %d not indented by two: %q", i, l)
+		}
+	}
+	if words := strings.Fields(got); strings.Join(words, " ") != "Buy oat milk and call mum about the weekend" {
+		t.Errorf("words lost in wrapping: %q", got)
+	}
+}
+
+func TestCompactDur(t *testing.T) {
+	for _, tc := range []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{59*time.Second + 400*time.Millisecond, "59s"},
+		{59*time.Second + 600*time.Millisecond, "1m"},
+		{59 * time.Minute, "59m"},
+		{time.Hour + 59*time.Minute, "1h"},
+		{23*time.Hour + 59*time.Minute, "23h"},
+		{24 * time.Hour, "1d"},
+		{49 * time.Hour, "2d"},
+	} {
+		if got := compactDur(tc.d); got != tc.want {
+			t.Errorf("compactDur(%v) = %q, want %q", tc.d, got, tc.want)
+		}
+	}
+}
+
+func TestListRowsFitLayout(t *testing.T) {
+	now := time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC)
+	tasks := []Task{
+		{ID: 1, Title: "Buy oat milk 🥛 and 茶 and call mum about the weekend plans before Friday", DueAt: now.Add(50 * time.Hour), Priority: "A"},
+		{ID: 2, Title: "Read https://example.org/a/very/long/path/that/does/not/wrap/well", Done: true},
+		{ID: 3, Title: "Review PR #work #urgent #followup", DueAt: now.Add(-time.Hour)},
+		{ID: 4, Title: "Short"},
+	}
+	for _, width := range []int{20, 59, 60, 119, 120, 180} {
+		for _, accessible := range []bool{false, true} {
+			m := &Model{Width: width, Height: 40, Clock: fixedClock{now}, Accessible: accessible, Tasks: tasks}
+			for i := range m.Tasks {
+				m.Tasks[i].SetTitle(m.Tasks[i].Title)
+			}
+			l := m.layout()
+			out := m.scanZones(m.viewList(themes.All[0]))
+			for _, line := range strings.Split(out, "\n") {
+				if w := ansi.StringWidth(line); w > l.width {
+					t.Errorf("width %d, accessible %v: row is %d wide, over %d: %q", width, accessible, w, l.width, ansi.Strip(line))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/nirabyte/todo/internal/config"
+)
+
+// linkPattern finds URLs and file paths in a title. A path has to start a
+// word with /, ~/, ./ or ../ so that "and/or" isn't one.
+var linkPattern = regexp.MustCompile(`(?:^|\s)((?:https?|file)://\S+|(?:~|\.\.?)?/[^\s/]\S*)`)
+
+// linkSpans returns where the links in title start and end, leaving
+// closing punctuation out so "see https://x.org." links to the site.
+func linkSpans(title string) [][2]int {
+	var spans [][2]int
+	for _, m := range linkPattern.FindAllStringSubmatchIndex(title, -1) {
+		start, end := m[2], m[3]
+		end = start + len(strings.TrimRight(title[start:end], `.,;:!?)]}'"`))
+		if end > start {
+			spans = append(spans, [2]int{start, end})
+		}
+	}
+	return spans
+}
+
+// Links lists what the task points at: the page it was imported from,
+// then the URLs and paths in its title.
+func (t Task) Links() []string {
+	var links []string
+	if t.URL != "" {
+		links = append(links, t.URL)
+	}
+	for _, s := range linkSpans(t.Title) {
+		if l := t.Title[s[0]:s[1]]; l != t.URL {
+			links = append(links, l)
+		}
+	}
+	return links
+}
+
+// linkTarget makes a link something a terminal or opener understands:
+// paths are expanded to absolute file:// URLs.
+func linkTarget(link string) string {
+	if strings.Contains(link, "://") {
+		return link
+	}
+	path, err := filepath.Abs(config.ExpandPath(link))
+	if err != nil {
+		return link
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+// renderTitle styles title, underlining its links and making them OSC 8
+// hyperlinks. The rest of the title links to the task's URL, if any.
+func renderTitle(t Task, style lipgloss.Style) string {
+	link := func(target, text string) string {
+		if target == "" {
+			return text
+		}
+		return ansi.SetHyperlink(linkTarget(target)) + text + ansi.ResetHyperlink()
+	}
+	var b strings.Builder
+	at := 0
+	for _, s := range linkSpans(t.Title) {
+		b.WriteString(link(t.URL, style.Render(t.Title[at:s[0]])))
+		l := t.Title[s[0]:s[1]]
+		b.WriteString(link(l, style.Underline(true).Render(l)))
+		at = s[1]
+	}
+	b.WriteString(link(t.URL, style.Render(t.Title[at:])))
+	return b.String()
+}
+
+// OpenLink hands target, a URL or a file path, to the system's default
+// application without waiting for it.
+func OpenLink(target string) error {
+	target = linkTarget(target)
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", target)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", target)
+	default:
+		cmd = exec.Command("xdg-open", target)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// Copy puts text on the system clipboard. Without one, as over SSH, it
+// asks the terminal to do it with OSC 52.
+func Copy(text string) error {
+	if clipboard.WriteAll(text) == nil {
+		return nil
+	}
+	seq := osc52.New(text)
+	if os.Getenv("TMUX") != "" {
+		seq = seq.Tmux()
+	}
+	_, err := seq.WriteTo(os.Stderr)
+	return err
+}
+
+func (m *Model) opener() func(string) error {
+	if m.Open == nil {
+		return OpenLink
+	}
+	return m.Open
+}
+
+func (m *Model) copier() func(string) error {
+	if m.Copy == nil {
+		return Copy
+	}
+	return m.Copy
+}
+
+// linkPick is the choice StatePickingLink waits for: which of choices to
+// hand to do.
+type linkPick struct {
+	verb    string
+	choices []string
+	do      func(string) error
+}
+
+// openLink opens the selected task's link, or lets the user pick one when
+// it has several.
+func (m *Model) openLink() tea.Cmd {
+	if len(m.Tasks) == 0 {
+		return nil
+	}
+	return m.pickLink("Open", m.Tasks[m.Cursor].Links(), m.opener())
+}
+
+// copyLink copies the selected task's title, or one of its links.
+func (m *Model) copyLink() tea.Cmd {
+	if len(m.Tasks) == 0 {
+		return nil
+	}
+	t := m.Tasks[m.Cursor]
+	return m.pickLink("Copy", append([]string{t.Title}, t.Links()...), m.copier())
+}
+
+func (m *Model) pickLink(verb string, choices []string, do func(string) error) tea.Cmd {
+	switch len(choices) {
+	case 0:
+		return nil
+	case 1:
+		return m.runLink(verb, do, choices[0])
+	}
+	m.picking = &linkPick{verb: verb, choices: choices, do: do}
+	m.State = StatePickingLink
+	return nil
+}
+
+// runLink hands choice to do off the UI goroutine, reporting a failure
+// under verb.
+func (m *Model) runLink(verb string, do func(string) error, choice string) tea.Cmd {
+	return func() tea.Msg {
+		m.Report(verb, do(choice))
+		return nil
+	}
+}
+
+// updatePicking handles a key while a link is being picked: 1-9 pick,
+// Enter takes the first and anything else cancels.
+func (m *Model) updatePicking(key string) tea.Cmd {
+	p := m.picking
+	m.picking = nil
+	m.State = StateBrowse
+	if key == "enter" {
+		key = "1"
+	}
+	var n int
+	if _, err := fmt.Sscanf(key, "%d", &n); err != nil || len(key) != 1 || n < 1 || n > len(p.choices) {
+		return nil
+	}
+	return m.runLink(p.verb, p.do, p.choices[n-1])
+}
+
+// viewPicking lists the choices under the selected row.
+func (m *Model) viewPicking(width int) string {
+	var s strings.Builder
+	s.WriteString(m.picking.verb + ":")
+	for i, c := range m.picking.choices {
+		if i == 9 {
+			break
+		}
+		s.WriteString(fmt.Sprintf("\n%d. %s", i+1, ansi.Truncate(c, width-4, "…")))
+	}
+	return s.String()
+}
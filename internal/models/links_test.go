@@ -0,0 +1,70 @@
+package models
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLinkSpans(t *testing.T) {
+	for _, tc := range []struct {
+		title string
+		want  []string
+	}{
+		{"see https://x.org.", []string{"https://x.org"}},
+		{"Read https://x.org/a?b=c, then /tmp/notes.md!", []string{"https://x.org/a?b=c", "/tmp/notes.md"}},
+		{"Pick one and/or the other", nil},
+		{"Check ~/notes.md and ./todo.md", []string{"~/notes.md", "./todo.md"}},
+		{"Open ../plan.txt)", []string{"../plan.txt"}},
+		{"file:///tmp/a.txt is here", []string{"file:///tmp/a.txt"}},
+		{"Dates like 10/18 and a lone / stay", nil},
+		{"say \"https://x.org\"", nil},
+		{"https://x.org", []string{"https://x.org"}},
+	} {
+		var got []string
+		for _, s := range linkSpans(tc.title) {
+			got = append(got, tc.title[s[0]:s[1]])
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("linkSpans(%q) = %q, want %q", tc.title, got, tc.want)
+		}
+	}
+}
+
+func TestLinkTarget(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := t.TempDir()
+	t.Chdir(dir)
+	for _, tc := range []struct {
+		link, want string
+	}{
+		{"https://x.org/a?b=c", "https://x.org/a?b=c"},
+		{"file:///tmp/a.txt", "file:///tmp/a.txt"},
+		{"/tmp/a b.txt", "file:///tmp/a%20b.txt"},
+		{"~/notes.md", "file://" + filepath.ToSlash(filepath.Join(home, "notes.md"))},
+		{"./todo.md", "file://" + filepath.ToSlash(filepath.Join(dir, "todo.md"))},
+		{"../plan.txt", "file://" + filepath.ToSlash(filepath.Join(filepath.Dir(dir), "plan.txt"))},
+	} {
+		if got := linkTarget(tc.link); got != tc.want {
+			t.Errorf("linkTarget(%q) = %q, want %q", tc.link, got, tc.want)
+		}
+	}
+}
+
+func TestOpenLinkFailureIsReported(t *testing.T) {
+	m := &Model{Width: 120, Height: 30, Tasks: []Task{{ID: 1, Title: "Read https://x.org"}}}
+	m.Open = func(string) error { return errors.New("xdg-open: not found") }
+	wait := m.waitForReport()
+	cmd := m.openLink()
+	if cmd == nil {
+		t.Fatal("no command to open the link")
+	}
+	cmd()
+	m.Update(wait())
+	if status := m.failureStatus(); !strings.Contains(status, "Open failed: xdg-open: not found") {
+		t.Errorf("status %q", status)
+	}
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/nirabyte/todo/internal/config"
+)
+
+// DataVersion is the version of the data file format this build reads and
+// writes. Files without a version field are version 1.
+const DataVersion = 3
+
+// ErrNewerVersion means the data file was written by a newer todo.
+var ErrNewerVersion = errors.New("data file is from a newer version of todo")
+
+// migrations[i] upgrades a version i+1 file to version i+2, so there is
+// one per version after the first. They work on the decoded JSON rather
+// than on AppData, so each keeps seeing the format it was written for.
+var migrations = []func(doc map[string]any) error{
+	migrateNotified,
+	migrateTags,
+}
+
+// migrateNotified (1 → 2) records a sent deadline alert as lastAlert,
+// which reminders use instead of the notified flag.
+func migrateNotified(doc map[string]any) error {
+	tasks, _ := doc["tasks"].([]any)
+	for _, v := range tasks {
+		t, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		if notified, _ := t["notified"].(bool); !notified {
+			continue
+		}
+		if last, _ := t["lastAlert"].(string); last == "" {
+			if due, ok := t["dueAt"].(string); ok {
+				t["lastAlert"] = due
+			}
+		}
+	}
+	return nil
+}
+
+// migrateTags (2 → 3) fills in the tags, projects and contexts of tasks
+// saved before they were taken from the title.
+func migrateTags(doc map[string]any) error {
+	tasks, _ := doc["tasks"].([]any)
+	for _, v := range tasks {
+		t, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		title, _ := t["title"].(string)
+		fill := func(key string, words []string) {
+			if have, _ := t[key].([]any); len(have) == 0 && len(words) > 0 {
+				t[key] = words
+			}
+		}
+		fill("tags", ParseTags(title))
+		fill("projects", parseWords(projectPattern, title))
+		fill("contexts", parseWords(contextPattern, title))
+	}
+	return nil
+}
+
+// fileVersion reads the version field of a data file.
+func fileVersion(data []byte) (int, error) {
+	var head struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return 0, err
+	}
+	return max(head.Version, 1), nil
+}
+
+// migrate upgrades data from version from to DataVersion.
+func migrate(data []byte, from int) ([]byte, error) {
+	var doc map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	// Numbers stay as written: task IDs don't fit in a float64.
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	for v := from; v < DataVersion; v++ {
+		if err := migrations[v-1](doc); err != nil {
+			return nil, fmt.Errorf("migrate data from version %d to %d: %w", v, v+1, err)
+		}
+		doc["version"] = v + 1
+	}
+	return json.Marshal(doc)
+}
+
+// backup copies the data file as it was before migrating from version
+// from, keeping any earlier backup of the same version.
+func backup(data []byte, from int) (string, error) {
+	path := fmt.Sprintf("%s.v%d.bak", config.DataFile, from)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if errors.Is(err, os.ErrExist) {
+		return path, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return "", err
+	}
+	return path, f.Close()
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/nirabyte/todo/internal/config"
+)
+
+func TestMigrateBackfillsTags(t *testing.T) {
+	t.Chdir(t.TempDir())
+	v2 := `{"version": 2, "themeIndex": 0, "sortMode": 0, "tasks": [
+		{"id": 1, "title": "Fix #Bug in +api @work", "dueAt": "0001-01-01T00:00:00Z"},
+		{"id": 2, "title": "Renamed #new", "tags": ["old"], "dueAt": "0001-01-01T00:00:00Z"},
+		{"id": 3, "title": "Plain", "dueAt": "0001-01-01T00:00:00Z"}
+	]}`
+	if err := os.WriteFile(config.DataFile, []byte(v2), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ReadData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Version != DataVersion {
+		t.Errorf("version %d, want %d", data.Version, DataVersion)
+	}
+	fix := data.Tasks[0]
+	if !slices.Equal(fix.Tags, []string{"bug"}) || !slices.Equal(fix.Projects, []string{"api"}) || !slices.Equal(fix.Contexts, []string{"work"}) {
+		t.Errorf("backfilled %q %q %q", fix.Tags, fix.Projects, fix.Contexts)
+	}
+	if got := data.Tasks[1].Tags; !slices.Equal(got, []string{"old"}) {
+		t.Errorf("tags already saved became %q", got)
+	}
+	if got := data.Tasks[2].Tags; len(got) != 0 {
+		t.Errorf("plain task got tags %q", got)
+	}
+	if _, err := os.Stat(config.DataFile + ".v2.bak"); err != nil {
+		t.Errorf("no backup: %v", err)
+	}
+}
+
+func TestMigrateFromVersion1(t *testing.T) {
+	t.Chdir(t.TempDir())
+	// Version 1 files had no version field. The ID doesn't fit a float64.
+	v1 := `{"themeIndex": 2, "sortMode": 0, "tasks": [
+		{"id": 1772441000000000123, "title": "Call mum", "dueAt": "2026-03-02T09:00:00Z", "notified": true},
+		{"id": 2, "title": "Buy milk", "dueAt": "2026-03-03T09:00:00Z", "notified": false}
+	]}`
+	if err := os.WriteFile(config.DataFile, []byte(v1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ReadData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mum, milk := data.Tasks[0], data.Tasks[1]
+	if mum.ID != 1772441000000000123 {
+		t.Errorf("ID = %d", mum.ID)
+	}
+	if want := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC); !mum.LastAlert.Equal(want) {
+		t.Errorf("sent alert: lastAlert = %v, want %v", mum.LastAlert, want)
+	}
+	if !milk.LastAlert.IsZero() {
+		t.Errorf("unsent alert: lastAlert = %v", milk.LastAlert)
+	}
+	if data.ThemeIndex != 2 {
+		t.Errorf("themeIndex = %d", data.ThemeIndex)
+	}
+
+	bak, err := os.ReadFile(config.DataFile + ".v1.bak")
+	if err != nil || string(bak) != v1 {
+		t.Errorf("backup = %q, %v; want the file as it was", bak, err)
+	}
+	raw, err := os.ReadFile(config.DataFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version, err := fileVersion(raw); err != nil || version != DataVersion {
+		t.Errorf("file left at version %d, %v", version, err)
+	}
+
+	// Another old file later doesn't replace the first backup.
+	if err := os.WriteFile(config.DataFile, []byte(`{"tasks": []}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadData(); err != nil {
+		t.Fatal(err)
+	}
+	if bak, _ := os.ReadFile(config.DataFile + ".v1.bak"); string(bak) != v1 {
+		t.Errorf("backup replaced with %q", bak)
+	}
+}
+
+func TestReadDataRefusesNewerVersion(t *testing.T) {
+	t.Chdir(t.TempDir())
+	newer := `{"version": 99, "tasks": [{"id": 1, "title": "From the future"}]}`
+	if err := os.WriteFile(config.DataFile, []byte(newer), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadData(); !errors.Is(err, ErrNewerVersion) {
+		t.Fatalf("err = %v, want ErrNewerVersion", err)
+	}
+	if _, err := LoadData(); !errors.Is(err, ErrNewerVersion) {
+		t.Errorf("LoadData err = %v, want ErrNewerVersion", err)
+	}
+	if raw, _ := os.ReadFile(config.DataFile); string(raw) != newer {
+		t.Errorf("file rewritten: %s", raw)
+	}
+	if matches, _ := filepath.Glob(config.DataFile + ".*.bak"); len(matches) > 0 {
+		t.Errorf("backups made: %q", matches)
+	}
+}
@@ -1,9 +1,14 @@
 package models
 
 import (
+	"context"
+	"math/rand"
+	"net"
 	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/nirabyte/todo/internal/config"
+	"github.com/nirabyte/todo/internal/notify"
 )
 
 type AppState int
@@ -13,8 +18,25 @@ const (
 	StateEditing
 	StateCreating
 	StateSettingTime
+	StateSettingReminders
+	StateSnoozing
+	StateFocus
+	StatePickingLink
+	StateBoard
+	StateAgenda
+	StateCalendar
 )
 
+// prompting reports whether a text input or the link picker is open. They
+// hold on to the cursor, so the task list must not change under them.
+func (m *Model) prompting() bool {
+	switch m.State {
+	case StateEditing, StateCreating, StateSettingTime, StateSettingReminders, StateSnoozing, StatePickingLink:
+		return true
+	}
+	return false
+}
+
 type SortMode int
 
 const (
@@ -65,17 +87,80 @@ type Task struct {
 	DueAt    time.Time `json:"dueAt"`
 	Notified bool      `json:"notified"`
 
+	CreatedAt   time.Time `json:"createdAt,omitzero"`
+	CompletedAt time.Time `json:"completedAt,omitzero"`
+
+	// Priority is a todo.txt priority, "A" (highest) to "Z", or empty.
+	Priority string `json:"priority,omitempty"`
+
+	// Recurrence is an iCalendar RRULE value such as "FREQ=WEEKLY", kept so
+	// calendars see a repeating task as repeating.
+	Recurrence string `json:"recurrence,omitempty"`
+
+	// Reminders are offsets before DueAt to alert at; empty means
+	// DefaultReminders. LastAlert is the time alerts were last handled up
+	// to, and nothing alerts before SnoozedUntil.
+	Reminders    []config.Duration `json:"reminders,omitempty"`
+	LastAlert    time.Time         `json:"lastAlert,omitzero"`
+	SnoozedUntil time.Time         `json:"snoozedUntil,omitzero"`
+
+	// Tags are the #hashtags in the title. Notify, when set, names the
+	// notifiers for this task and overrides routing by tag.
+	Tags   []string `json:"tags,omitempty"`
+	Notify []string `json:"notify,omitempty"`
+
+	// Projects and Contexts are the todo.txt +project and @context words in
+	// the title. Extra keeps key:value pairs written by other tools, in
+	// order, so they survive a round trip.
+	Projects []string   `json:"projects,omitempty"`
+	Contexts []string   `json:"contexts,omitempty"`
+	Extra    []KeyValue `json:"extra,omitempty"`
+
+	// URL links the task to where it came from, such as an issue.
+	URL string `json:"url,omitempty"`
+
+	// NotesFile is the Markdown file, by absolute path, whose checklist
+	// the task is kept in step with.
+	NotesFile string `json:"notesFile,omitempty"`
+
+	// Status is the board column: StatusTodo, StatusDoing or StatusDone.
+	// Read it through Stage.
+	Status string `json:"status,omitempty"`
+
+	// Sessions are the completed pomodoro work phases. Tracked holds the
+	// start/stop timer intervals; a running one has no End yet.
+	Sessions []Interval `json:"sessions,omitempty"`
+	Tracked  []Interval `json:"tracked,omitempty"`
+
+	// Clock holds when each JSON field last changed, in Unix nanoseconds,
+	// for last-writer-wins sync. See SyncClocks.
+	Clock map[string]int64 `json:"clock,omitempty"`
+
 	// Animation States
 	IsAnimatingCheck bool      `json:"-"`
 	IsDeleting       bool      `json:"-"`
 	AnimType         int       `json:"-"`
 	AnimStart        time.Time `json:"-"`
+	AnimSeed         int64     `json:"-"`
+}
+
+type KeyValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
 }
 
 type AppData struct {
+	Version    int      `json:"version"`
 	ThemeIndex int      `json:"themeIndex"`
 	SortMode   SortMode `json:"sortMode"`
 	Tasks      []Task   `json:"tasks"`
+
+	// GoalDay is the day the daily goal was last celebrated, YYYY-MM-DD.
+	GoalDay string `json:"goalDay,omitempty"`
+
+	// Tombstones and SyncedAt are kept while syncing with a server.
+	Tombstones []Tombstone `json:"tombstones,omitempty"`
+	SyncedAt   time.Time   `json:"syncedAt,omitzero"`
 }
 
 type TickMsg struct{}
@@ -86,10 +171,97 @@ type Model struct {
 	SortMode   SortMode
 	ThemeIndex int
 	LastAnim   int
+	Accessible bool
+
+	Celebrations bool
+	DailyGoal    int
+	GoalDay      string
+	Celebration  *Celebration
+
+	Focus         *Focus
+	FocusSettings FocusSettings
+
+	// BoardColumns groups the board: BoardByStatus, BoardByTag or
+	// BoardByPriority.
+	BoardColumns string
+
+	// Daemon is the control socket of the background reminder daemon.
+	// While one answers there, it sends the notifications instead. Socket
+	// is this app's own, where other writers ask it to reload.
+	Daemon          string
+	Socket          string
+	Notifier        notify.Notifier
+	OverdueInterval time.Duration
+
+	// Open shows a task's link and Copy puts text on the clipboard; nil
+	// means OpenLink and Copy.
+	Open func(target string) error
+	Copy func(text string) error
+
+	// OnSave hooks are called in order with what Save wrote.
+	OnSave []func(AppData)
+
+	// Notes, if set, merges the list with a notes file before each save,
+	// so edits made there are never written over.
+	Notes func(tasks []Task, now time.Time) ([]Task, bool, error)
+
+	// Sync, if set, merges the list with a sync server every SyncInterval.
+	Sync         func(context.Context, AppData) (AppData, error)
+	SyncInterval time.Duration
 
 	Cursor    int
 	Width     int
 	Height    int
 	TextInput textinput.Model
-}
 
+	// Clock and Rand drive every time- and chance-dependent decision, so a
+	// fixed clock and seed replay the same frames.
+	Clock Clock
+	Rand  *rand.Rand
+
+	animating bool
+	counting  bool
+	dueGen    int
+	actions   chan ActionMsg
+
+	// alerting holds the tasks whose alert is being sent (zero time) or
+	// failed and is retried at the time given. alertErr is the last
+	// failure, until a send succeeds.
+	alerting map[int64]time.Time
+	alertErr error
+
+	// daemonUp is whether the daemon was running when last probed, at
+	// daemonAt.
+	daemonUp bool
+	daemonAt time.Time
+
+	reports  reports
+	failures map[string]error
+
+	syncing    bool
+	syncErr    error
+	syncQueued int
+
+	listener net.Listener
+	reloads  chan struct{}
+	// base is the list as last read from or written to the data file, by
+	// ID. Save rebases on it, so changes other programs made meanwhile
+	// are kept. Before the first read it is nil.
+	base map[int64]Task
+
+	picking *linkPick
+	// offset is the first task row the list shows.
+	offset     int
+	helpHeight int
+
+	boardCol int
+	boardRow int
+
+	// calDay is the day selected on the calendar, at midnight. While
+	// calPick is set, the keys work on the calendar's task list instead.
+	calDay  time.Time
+	calPick bool
+
+	targets []target
+	zones   []zone
+}
@@ -0,0 +1,170 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Clickable parts of the screen are wrapped in zero-width marker sequences
+// while View builds it. Once the frame is laid out, scanZones finds the
+// markers, notes where they ended up and strips them, so hit-testing
+// follows whatever lipgloss did to the layout.
+
+// target is what a click on a zone does: select Row (and toggle it if
+// Check), or press Key. On the board, Col is the column of the card, and
+// on the calendar a Day of the month selects that day.
+type target struct {
+	Row   int
+	Col   int
+	Day   int
+	Check bool
+	Key   string
+}
+
+// zone is where a target was drawn, from (X0, Y0) up to but not
+// including (X1, Y1).
+type zone struct {
+	target
+	X0, Y0, X1, Y1 int
+}
+
+// zone wraps s in the markers for t.
+func (m *Model) zone(t target, s string) string {
+	id := len(m.targets)
+	m.targets = append(m.targets, t)
+	return fmt.Sprintf("\x1b[%d;1z%s\x1b[%d;2z", id, s, id)
+}
+
+// scanZones records where the marked zones of view are and returns view
+// without the markers.
+func (m *Model) scanZones(view string) string {
+	m.zones = m.zones[:0]
+	open := map[int]zone{}
+	var b strings.Builder
+	b.Grow(len(view))
+	x, y := 0, 0
+	var state byte
+	for len(view) > 0 {
+		seq, width, n, newState := ansi.DecodeSequence(view, state, nil)
+		state = newState
+		view = view[n:]
+		if id, end, ok := parseMarker(seq); ok {
+			if !end {
+				open[id] = zone{X0: x, Y0: y}
+			} else if z, ok := open[id]; ok && id < len(m.targets) {
+				z.target, z.X1, z.Y1 = m.targets[id], x, y
+				m.zones = append(m.zones, z)
+			}
+			continue
+		}
+		b.WriteString(seq)
+		if seq == "\n" {
+			x, y = 0, y+1
+		} else {
+			x += width
+		}
+	}
+	m.targets = m.targets[:0]
+	return b.String()
+}
+
+func parseMarker(seq string) (id int, end bool, ok bool) {
+	body, found := strings.CutPrefix(seq, "\x1b[")
+	if !found || !strings.HasSuffix(body, "z") {
+		return 0, false, false
+	}
+	num, kind, found := strings.Cut(strings.TrimSuffix(body, "z"), ";")
+	id, err := strconv.Atoi(num)
+	if !found || err != nil || (kind != "1" && kind != "2") {
+		return 0, false, false
+	}
+	return id, kind == "2", true
+}
+
+// hit finds the innermost zone at (x, y). A zone spanning several lines
+// covers them from its starting column on.
+func (m *Model) hit(x, y int) (target, bool) {
+	var best *zone
+	for i := range m.zones {
+		z := &m.zones[i]
+		in := y >= z.Y0 && y <= z.Y1 && x >= z.X0
+		if z.Y0 == z.Y1 {
+			in = in && x < z.X1
+		}
+		if in && (best == nil || z.Y0 > best.Y0 || z.Y0 == best.Y0 && z.X0 > best.X0) {
+			best = z
+		}
+	}
+	if best == nil {
+		return target{}, false
+	}
+	return best.target, true
+}
+
+// updateMouse handles clicks and the wheel in the list and the other
+// views of it.
+func (m *Model) updateMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.prompting() || m.State == StateFocus || msg.Action != tea.MouseActionPress {
+		return m, nil
+	}
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		return m.Update(keyPress("up"))
+	case tea.MouseButtonWheelDown:
+		return m.Update(keyPress("down"))
+	case tea.MouseButtonLeft:
+	default:
+		return m, nil
+	}
+	t, ok := m.hit(msg.X, msg.Y)
+	switch {
+	case !ok:
+		return m, nil
+	case t.Key != "":
+		return m.Update(keyPress(t.Key))
+	case t.Day > 0:
+		m.calDay = m.calDay.AddDate(0, 0, t.Day-m.calDay.Day())
+		m.calPick = false
+	case t.Row < len(m.Tasks) && m.State == StateCalendar:
+		m.Cursor = t.Row
+		m.calPick = true
+	case t.Row < len(m.Tasks) && m.State == StateAgenda:
+		m.Cursor = t.Row
+	case t.Row < len(m.Tasks) && m.State == StateBoard:
+		m.Cursor = t.Row
+		m.boardFocus(m.boardColumns(), t.Col, 0)
+	case t.Row < len(m.Tasks):
+		m.Cursor = t.Row
+		if t.Check {
+			return m.Update(keyPress(" "))
+		}
+	}
+	return m, nil
+}
+
+// keyPress is the message for pressing key, as spelled by KeyMsg.String.
+func keyPress(key string) tea.KeyMsg {
+	switch key {
+	case "up":
+		return tea.KeyMsg{Type: tea.KeyUp}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	case "pgup":
+		return tea.KeyMsg{Type: tea.KeyPgUp}
+	case "pgdown":
+		return tea.KeyMsg{Type: tea.KeyPgDown}
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case " ":
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+}
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var markerPattern = regexp.MustCompile(`\x1b\[\d+;[12]z`)
+
+func TestScanZonesAfterLayout(t *testing.T) {
+	m := &Model{}
+	box := lipgloss.NewStyle().Border(lipgloss.NormalBorder()).Padding(0, 1).
+		Render(m.zone(target{Row: 1}, "Buy milk"))
+	frame := lipgloss.JoinVertical(lipgloss.Left, "Tasks",
+		lipgloss.JoinHorizontal(lipgloss.Top, box, "  ", m.zone(target{Key: "n"}, "New")))
+
+	out := m.scanZones(frame)
+	if markerPattern.MatchString(out) {
+		t.Errorf("markers left in:\n%q", out)
+	}
+	// The box's border is on the line under the title, and zones are
+	// listed as they end.
+	want := []zone{
+		{target: target{Key: "n"}, X0: 14, Y0: 1, X1: 17, Y1: 1},
+		{target: target{Row: 1}, X0: 2, Y0: 2, X1: 10, Y1: 2},
+	}
+	if len(m.zones) != len(want) {
+		t.Fatalf("zones = %+v, want %+v", m.zones, want)
+	}
+	for i, w := range want {
+		if m.zones[i] != w {
+			t.Errorf("zone %d = %+v, want %+v", i, m.zones[i], w)
+		}
+	}
+	if len(m.targets) != 0 {
+		t.Errorf("targets not reset: %+v", m.targets)
+	}
+}
+
+func TestHitNestedAndMultiLineZones(t *testing.T) {
+	m := &Model{}
+	row := m.zone(target{Row: 2}, "[ ] "+m.zone(target{Row: 2, Check: true}, "x")+" Buy milk")
+	note := lipgloss.NewStyle().PaddingLeft(3).Render(m.zone(target{Key: "e"}, "one\ntwo\nthree"))
+	m.scanZones(lipgloss.JoinVertical(lipgloss.Left, row, note))
+
+	for _, tc := range []struct {
+		x, y int
+		want target
+		ok   bool
+	}{
+		{0, 0, target{Row: 2}, true},
+		{4, 0, target{Row: 2, Check: true}, true},
+		{6, 0, target{Row: 2}, true},
+		{13, 0, target{Row: 2}, true},
+		{14, 0, target{}, false},
+		{3, 1, target{Key: "e"}, true},
+		{7, 2, target{Key: "e"}, true},
+		{3, 3, target{Key: "e"}, true},
+		{2, 2, target{}, false},
+		{3, 4, target{}, false},
+	} {
+		got, ok := m.hit(tc.x, tc.y)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("hit(%d, %d) = %+v, %v, want %+v, %v", tc.x, tc.y, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestParseMarker(t *testing.T) {
+	for _, tc := range []struct {
+		seq     string
+		id      int
+		end, ok bool
+	}{
+		{"\x1b[3;1z", 3, false, true},
+		{"\x1b[12;2z", 12, true, true},
+		{"\x1b[3;3z", 0, false, false},
+		{"\x1b[3z", 0, false, false},
+		{"\x1b[1;2m", 0, false, false},
+		{"z", 0, false, false},
+	} {
+		id, end, ok := parseMarker(tc.seq)
+		if id != tc.id || end != tc.end || ok != tc.ok {
+			t.Errorf("parseMarker(%q) = %d, %v, %v", tc.seq, id, end, ok)
+		}
+	}
+}
+
+func TestViewHasNoMarkers(t *testing.T) {
+	t.Chdir(t.TempDir())
+	now := time.Date(2026, 3, 4, 9, 0, 0, 0, time.Local)
+	for _, state := range []AppState{StateBrowse, StateBoard, StateAgenda, StateCalendar} {
+		m := &Model{
+			Width: 100, Height: 30, Clock: fixedClock{now}, State: state,
+			Tasks: []Task{
+				{ID: 1, Title: "Buy milk #home", DueAt: now.Add(time.Hour)},
+				{ID: 2, Title: "Read https://x.org #work", DueAt: now.Add(26 * time.Hour)},
+			},
+		}
+		if state == StateBoard {
+			m.openBoard()
+		}
+		out := m.View()
+		if markerPattern.MatchString(out) {
+			t.Errorf("state %d: markers left in the view:\n%q", state, out)
+		}
+		if len(m.zones) == 0 {
+			t.Errorf("state %d: no zones", state)
+		}
+	}
+}
+
+func TestClickSelectsRow(t *testing.T) {
+	t.Chdir(t.TempDir())
+	m := &Model{Width: 80, Height: 24, Tasks: []Task{{ID: 1, Title: "Buy milk"}, {ID: 2, Title: "Call mum"}}}
+	m.View()
+	for _, z := range m.zones {
+		if z.Row == 1 && !z.Check && z.Key == "" {
+			m.Update(tea.MouseMsg{X: z.X1 - 1, Y: z.Y0, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+			if m.Cursor != 1 {
+				t.Errorf("cursor = %d after clicking the second row", m.Cursor)
+			}
+			return
+		}
+	}
+	t.Fatalf("no zone for the second row in %+v", m.zones)
+}
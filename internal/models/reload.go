@@ -0,0 +1,147 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nirabyte/todo/internal/control"
+)
+
+// ReloadMsg means another program rewrote the data file.
+type ReloadMsg struct{}
+
+// reloadRetryMsg retries a reload that had to wait for a text input.
+type reloadRetryMsg struct{}
+
+// listen opens the app's control socket, so the API server, the daemon
+// and todo commands can tell it about their writes. Without it, changes
+// made elsewhere show up only after a restart.
+func (m *Model) listen() tea.Cmd {
+	if m.Socket == "" {
+		return nil
+	}
+	l, err := control.Listen(m.Socket)
+	if err != nil {
+		// Another app has the list open and gets the news instead.
+		return nil
+	}
+	m.listener = l
+	m.reloads = make(chan struct{}, 1)
+	go control.Serve(l, func(cmd string) string {
+		switch cmd {
+		case control.CmdPing:
+			return "pong"
+		case control.CmdReload:
+			select {
+			case m.reloads <- struct{}{}:
+			default:
+			}
+			return "ok"
+		}
+		return "unknown command"
+	})
+	return waitForReload(m.reloads)
+}
+
+func waitForReload(ch chan struct{}) tea.Cmd {
+	return func() tea.Msg {
+		<-ch
+		return ReloadMsg{}
+	}
+}
+
+// reload adopts the tasks in the data file. While a text input is open
+// the cursor is in use, so it tries again shortly.
+func (m *Model) reload() tea.Cmd {
+	if m.prompting() {
+		return tea.Tick(500*time.Millisecond, func(time.Time) tea.Msg { return reloadRetryMsg{} })
+	}
+	data, err := ReadData()
+	if err != nil {
+		return nil
+	}
+	m.adopt(data.Tasks)
+	m.remember(data.Tasks)
+	return m.scheduleTimers()
+}
+
+// remember records tasks as what the data file holds.
+func (m *Model) remember(tasks []Task) {
+	m.base = make(map[int64]Task, len(tasks))
+	for _, t := range tasks {
+		m.base[t.ID] = t
+	}
+}
+
+// rebase brings the changes other programs made to the data file since
+// base into tasks, so a save doesn't write over them. It works field by
+// field: where both sides changed a field, tasks wins. Tasks added to the
+// file are appended, and tasks deleted from it are dropped even if they
+// were edited here meanwhile.
+func rebase(base map[int64]Task, tasks, file []Task) ([]Task, bool) {
+	inFile := map[int64]Task{}
+	for _, t := range file {
+		inFile[t.ID] = t
+	}
+	ours := map[int64]bool{}
+	var out []Task
+	changed := false
+	for _, t := range tasks {
+		ours[t.ID] = true
+		b, known := base[t.ID]
+		f, there := inFile[t.ID]
+		switch {
+		case !known:
+			out = append(out, t)
+		case !there:
+			changed = true
+		default:
+			t, took := mergeFields(b, t, f)
+			out = append(out, t)
+			changed = changed || took
+		}
+	}
+	for _, f := range file {
+		if _, known := base[f.ID]; !known && !ours[f.ID] {
+			out = append(out, f)
+			changed = true
+		}
+	}
+	return out, changed
+}
+
+// mergeFields takes the fields theirs changed since base and ours didn't.
+func mergeFields(base, ours, theirs Task) (Task, bool) {
+	was, mine, now := fields(base), fields(ours), fields(theirs)
+	took := false
+	for _, keys := range []map[string]json.RawMessage{was, now} {
+		for k := range keys {
+			if bytes.Equal(now[k], was[k]) || !bytes.Equal(mine[k], was[k]) {
+				continue
+			}
+			if v, ok := now[k]; ok {
+				mine[k] = v
+			} else {
+				delete(mine, k)
+			}
+			took = true
+		}
+	}
+	if !took {
+		return ours, false
+	}
+	merged := fromFields(mine)
+	merged.Clock = theirs.Clock
+	return merged, true
+}
+
+// Close releases the app's control socket.
+func (m *Model) Close() {
+	if m.listener != nil {
+		m.listener.Close()
+		os.Remove(m.Socket)
+	}
+}
@@ -0,0 +1,183 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nirabyte/todo/internal/config"
+	"github.com/nirabyte/todo/internal/notify"
+)
+
+// Actions offered on reminder notifications and by the snooze prompt.
+const (
+	ActionDone   = "done"
+	snoozePrefix = "snooze-"
+)
+
+var SnoozeChoices = []string{"5m", "15m", "1h", "tomorrow"}
+
+// DefaultReminders is used by tasks without their own offsets: one alert
+// at the deadline.
+var DefaultReminders = []config.Duration{0}
+
+// NextAlert returns when t should alert next, or the zero time when no
+// alert is pending. Alerts fall at each reminder offset before the
+// deadline, at the end of a snooze and, when overdue is positive, every
+// overdue interval after the deadline. Anything at or before LastAlert
+// has already been handled, and nothing fires during a snooze.
+func (t Task) NextAlert(overdue time.Duration) time.Time {
+	if t.Done {
+		return time.Time{}
+	}
+	last := t.LastAlert
+	if t.Notified && last.Before(t.DueAt) {
+		// Written before reminders existed: the deadline alert went out.
+		last = t.DueAt
+	}
+
+	var next time.Time
+	consider := func(at time.Time) {
+		if at.IsZero() || !at.After(last) {
+			return
+		}
+		if !t.SnoozedUntil.IsZero() && at.Before(t.SnoozedUntil) {
+			return
+		}
+		if next.IsZero() || at.Before(next) {
+			next = at
+		}
+	}
+
+	consider(t.SnoozedUntil)
+	if !t.DueAt.IsZero() {
+		offsets := t.Reminders
+		if len(offsets) == 0 {
+			offsets = DefaultReminders
+		}
+		for _, o := range offsets {
+			consider(t.DueAt.Add(-time.Duration(o)))
+		}
+		if overdue > 0 && next.IsZero() {
+			from := last
+			if t.SnoozedUntil.After(from) {
+				from = t.SnoozedUntil
+			}
+			n := from.Sub(t.DueAt)/overdue + 1
+			consider(t.DueAt.Add(max(n, 1) * overdue))
+		}
+	}
+	return next
+}
+
+// MarkAlerted records that every alert up to now has been sent.
+func (t *Task) MarkAlerted(now time.Time) {
+	t.LastAlert = now
+	if !t.DueAt.IsZero() && !now.Before(t.DueAt) {
+		t.Notified = true
+	}
+}
+
+// SetDue sets a new deadline. Offsets that already lie in the past are
+// treated as sent so they don't all fire at once.
+func (t *Task) SetDue(due, now time.Time) {
+	t.DueAt = due
+	t.Notified = false
+	t.SnoozedUntil = time.Time{}
+	t.LastAlert = now
+}
+
+// Snooze silences t until the time given by choice: a duration such as
+// "15m", or "tomorrow" for 9:00 the next morning.
+func (t *Task) Snooze(choice string, now time.Time) error {
+	until, err := snoozeUntil(choice, now)
+	if err != nil {
+		return err
+	}
+	t.SnoozedUntil = until
+	t.LastAlert = now
+	return nil
+}
+
+func snoozeUntil(choice string, now time.Time) (time.Time, error) {
+	if strings.TrimSpace(choice) == "tomorrow" {
+		y, m, d := now.AddDate(0, 0, 1).Date()
+		return time.Date(y, m, d, 9, 0, 0, 0, now.Location()), nil
+	}
+	d, err := config.ParseDuration(choice)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if d <= 0 {
+		return time.Time{}, fmt.Errorf("snooze must be in the future")
+	}
+	return now.Add(d), nil
+}
+
+// ApplyAction carries out a notification action on t.
+func (t *Task) ApplyAction(key string, now time.Time) error {
+	if key == ActionDone {
+		t.SetDone(true, now)
+		return nil
+	}
+	if choice, ok := strings.CutPrefix(key, snoozePrefix); ok {
+		return t.Snooze(choice, now)
+	}
+	return fmt.Errorf("unknown action %q", key)
+}
+
+// ParseReminders reads a comma-separated list of offsets such as
+// "1d, 1h, 0". An empty list means DefaultReminders. Offsets count back
+// from the deadline, so none can be negative.
+func ParseReminders(s string) ([]config.Duration, error) {
+	var offsets []config.Duration
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+		d, err := config.ParseDuration(part)
+		if err != nil {
+			return nil, err
+		}
+		if d < 0 {
+			return nil, fmt.Errorf("reminder %q is after the deadline; offsets count back from it", part)
+		}
+		offsets = append(offsets, config.Duration(d))
+	}
+	return offsets, nil
+}
+
+func FormatReminders(offsets []config.Duration) string {
+	parts := make([]string, len(offsets))
+	for i, o := range offsets {
+		parts[i] = config.FormatDuration(time.Duration(o))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Notification is the reminder sent for t at now. Its actions snooze the
+// task or mark it done.
+func (t Task) Notification(now time.Time) notify.Notification {
+	body := t.Title
+	switch left := t.DueAt.Sub(now).Round(time.Minute); {
+	case t.DueAt.IsZero():
+	case left > 0:
+		body = fmt.Sprintf("Due in %s: %s", shortDur(left), t.Title)
+	case left < 0:
+		body = fmt.Sprintf("Overdue by %s: %s", shortDur(-left), t.Title)
+	}
+
+	n := notify.Notification{
+		Title:  "Todo Alert!",
+		Body:   body,
+		TaskID: t.ID,
+		Tags:   t.Tags,
+		Due:    t.DueAt,
+		Sinks:  t.Notify,
+	}
+	for _, choice := range SnoozeChoices {
+		n.Actions = append(n.Actions, notify.Action{Key: snoozePrefix + choice, Label: "Snooze " + choice})
+	}
+	n.Actions = append(n.Actions, notify.Action{Key: ActionDone, Label: "Done"})
+	return n
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/nirabyte/todo/internal/config"
+)
+
+func TestParseReminders(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want []time.Duration
+		err  bool
+	}{
+		{"1d, 1h, 0", []time.Duration{24 * time.Hour, time.Hour, 0}, false},
+		{"1w,30m", []time.Duration{7 * 24 * time.Hour, 30 * time.Minute}, false},
+		{"", nil, false},
+		{" , ", nil, false},
+		{"-1h", nil, true},
+		{"1h, -0.5d", nil, true},
+		{"1h, soon", nil, true},
+	} {
+		got, err := ParseReminders(tc.in)
+		if (err != nil) != tc.err {
+			t.Errorf("ParseReminders(%q) err = %v", tc.in, err)
+			continue
+		}
+		var durs []time.Duration
+		for _, d := range got {
+			durs = append(durs, time.Duration(d))
+		}
+		if !slices.Equal(durs, tc.want) {
+			t.Errorf("ParseReminders(%q) = %v, want %v", tc.in, durs, tc.want)
+		}
+	}
+	offsets := []config.Duration{config.Duration(24 * time.Hour), config.Duration(90 * time.Minute), 0}
+	if got, err := ParseReminders(FormatReminders(offsets)); err != nil || !slices.Equal(got, offsets) {
+		t.Errorf("round trip of %v = %v, %v", offsets, got, err)
+	}
+}
+
+func TestNextAlert(t *testing.T) {
+	due := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	hours := func(h ...float64) []config.Duration {
+		var out []config.Duration
+		for _, x := range h {
+			out = append(out, config.Duration(time.Duration(x*float64(time.Hour))))
+		}
+		return out
+	}
+	for _, tc := range []struct {
+		name    string
+		task    Task
+		overdue time.Duration
+		want    time.Time
+	}{
+		{"first offset", Task{DueAt: due, Reminders: hours(1, 0)}, 0, due.Add(-time.Hour)},
+		{"after the first", Task{DueAt: due, Reminders: hours(1, 0), LastAlert: due.Add(-time.Hour)}, 0, due},
+		{"default", Task{DueAt: due}, 0, due},
+		{"all sent", Task{DueAt: due, LastAlert: due}, 0, time.Time{}},
+		{"overdue", Task{DueAt: due, LastAlert: due}, 30 * time.Minute, due.Add(30 * time.Minute)},
+		{"overdue later", Task{DueAt: due, LastAlert: due.Add(45 * time.Minute)}, 30 * time.Minute, due.Add(time.Hour)},
+		{"snoozed before the deadline", Task{DueAt: due, Reminders: hours(1, 0), LastAlert: due.Add(-time.Hour), SnoozedUntil: due.Add(-30 * time.Minute)}, 0, due.Add(-30 * time.Minute)},
+		{"snoozed past the deadline", Task{DueAt: due, LastAlert: due.Add(-time.Hour), SnoozedUntil: due.Add(10 * time.Minute)}, 0, due.Add(10 * time.Minute)},
+		{"snooze ended", Task{DueAt: due, LastAlert: due.Add(10 * time.Minute), SnoozedUntil: due.Add(10 * time.Minute)}, 30 * time.Minute, due.Add(30 * time.Minute)},
+		{"snooze without a deadline", Task{LastAlert: due, SnoozedUntil: due.Add(time.Hour)}, 0, due.Add(time.Hour)},
+		{"no deadline", Task{}, time.Hour, time.Time{}},
+		{"done", Task{DueAt: due, Done: true}, time.Hour, time.Time{}},
+		{"notified before reminders", Task{DueAt: due, Notified: true}, 0, time.Time{}},
+	} {
+		if got := tc.task.NextAlert(tc.overdue); !got.Equal(tc.want) {
+			t.Errorf("%s: NextAlert = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestSnooze(t *testing.T) {
+	now := time.Date(2026, 3, 1, 23, 30, 0, 0, time.Local)
+	var task Task
+	if err := task.Snooze("tomorrow", now); err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local); !task.SnoozedUntil.Equal(want) {
+		t.Errorf("snoozed until %v, want %v", task.SnoozedUntil, want)
+	}
+	if !task.LastAlert.Equal(now) {
+		t.Errorf("LastAlert = %v", task.LastAlert)
+	}
+	if err := task.Snooze("15m", now); err != nil || !task.SnoozedUntil.Equal(now.Add(15*time.Minute)) {
+		t.Errorf("snooze 15m = %v, %v", task.SnoozedUntil, err)
+	}
+	for _, bad := range []string{"0", "-5m", "later"} {
+		if err := task.Snooze(bad, now); err == nil {
+			t.Errorf("Snooze(%q) accepted", bad)
+		}
+	}
+	if !task.SnoozedUntil.Equal(now.Add(15 * time.Minute)) {
+		t.Errorf("a refused snooze changed it to %v", task.SnoozedUntil)
+	}
+}
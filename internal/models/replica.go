@@ -0,0 +1,211 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+// SyncClocks makes WriteData stamp every changed task field with the time
+// of the change and keep tombstones for deleted tasks, which Merge needs.
+// It is on while syncing with a server is configured.
+var SyncClocks bool
+
+// Tombstone records that a task was deleted, so the deletion spreads to
+// other replicas instead of the task coming back from them.
+type Tombstone struct {
+	ID int64     `json:"id"`
+	At time.Time `json:"at"`
+}
+
+// TombstoneTTL is how long a deletion is remembered. A replica that hasn't
+// synced for longer may bring the task back.
+const TombstoneTTL = 90 * 24 * time.Hour
+
+// PruneTombstones drops the tombstones older than TombstoneTTL at now.
+func PruneTombstones(list []Tombstone, now time.Time) []Tombstone {
+	return slices.DeleteFunc(list, func(ts Tombstone) bool {
+		return now.Sub(ts.At) > TombstoneTTL
+	})
+}
+
+// fields splits a task into its JSON fields, leaving out the clock.
+func fields(t Task) map[string]json.RawMessage {
+	raw, _ := json.Marshal(t)
+	var m map[string]json.RawMessage
+	json.Unmarshal(raw, &m)
+	delete(m, "clock")
+	return m
+}
+
+func fromFields(m map[string]json.RawMessage) Task {
+	var t Task
+	raw, _ := json.Marshal(m)
+	json.Unmarshal(raw, &t)
+	return t
+}
+
+// Stamp compares data with old, the file it is about to replace. Fields
+// that changed, and every field of a new task, get now as their clock.
+// Tasks missing from data become tombstones, and those past TombstoneTTL
+// are dropped. Tombstones and the last sync time carry over from old,
+// because Save doesn't keep them.
+func Stamp(old, data *AppData, now time.Time) {
+	stamp := now.UnixNano()
+	before := map[int64]Task{}
+	for _, t := range old.Tasks {
+		before[t.ID] = t
+	}
+	alive := map[int64]bool{}
+	for i := range data.Tasks {
+		t := &data.Tasks[i]
+		alive[t.ID] = true
+		prev, existed := before[t.ID]
+		clock := map[string]int64{}
+		for k, v := range prev.Clock {
+			clock[k] = v
+		}
+		cur, was := fields(*t), map[string]json.RawMessage{}
+		if existed {
+			was = fields(prev)
+		}
+		for k, v := range cur {
+			if w, ok := was[k]; !ok || !bytes.Equal(v, w) {
+				clock[k] = stamp
+			}
+		}
+		for k := range was {
+			if _, ok := cur[k]; !ok {
+				clock[k] = stamp
+			}
+		}
+		t.Clock = clock
+	}
+
+	tombs := map[int64]time.Time{}
+	for _, list := range [][]Tombstone{old.Tombstones, data.Tombstones} {
+		for _, ts := range list {
+			if ts.At.After(tombs[ts.ID]) {
+				tombs[ts.ID] = ts.At
+			}
+		}
+	}
+	for _, t := range old.Tasks {
+		if !alive[t.ID] {
+			tombs[t.ID] = now
+		}
+	}
+	data.Tombstones = data.Tombstones[:0]
+	for id, at := range tombs {
+		if !alive[id] {
+			data.Tombstones = append(data.Tombstones, Tombstone{ID: id, At: at})
+		}
+	}
+	data.Tombstones = PruneTombstones(data.Tombstones, now)
+	sortTombstones(data.Tombstones)
+	if old.SyncedAt.After(data.SyncedAt) {
+		data.SyncedAt = old.SyncedAt
+	}
+}
+
+func sortTombstones(list []Tombstone) {
+	slices.SortFunc(list, func(a, b Tombstone) int {
+		return a.At.Compare(b.At)
+	})
+}
+
+// lastChange is the newest clock of t's fields.
+func lastChange(t Task) int64 {
+	var last int64
+	for _, c := range t.Clock {
+		last = max(last, c)
+	}
+	return last
+}
+
+// Merge combines two replicas of the task list. For each field of a task
+// the value with the later clock wins; equal clocks are settled by the
+// values themselves, so both sides of a sync end up identical. A task is
+// gone if its tombstone is newer than every change to it. Settings and
+// the sync time come from a.
+func Merge(a, b AppData) AppData {
+	tombs := map[int64]time.Time{}
+	for _, ts := range append(slices.Clone(a.Tombstones), b.Tombstones...) {
+		if ts.At.After(tombs[ts.ID]) {
+			tombs[ts.ID] = ts.At
+		}
+	}
+	other := map[int64]Task{}
+	for _, t := range b.Tasks {
+		other[t.ID] = t
+	}
+
+	out := a
+	out.Tasks = nil
+	seen := map[int64]bool{}
+	add := func(t Task) {
+		if at, ok := tombs[t.ID]; ok && at.UnixNano() >= lastChange(t) {
+			return
+		}
+		delete(tombs, t.ID)
+		out.Tasks = append(out.Tasks, t)
+	}
+	for _, t := range a.Tasks {
+		seen[t.ID] = true
+		if u, ok := other[t.ID]; ok {
+			t = mergeTask(t, u)
+		}
+		add(t)
+	}
+	for _, t := range b.Tasks {
+		if !seen[t.ID] {
+			add(t)
+		}
+	}
+
+	out.Tombstones = nil
+	for id, at := range tombs {
+		out.Tombstones = append(out.Tombstones, Tombstone{ID: id, At: at})
+	}
+	sortTombstones(out.Tombstones)
+	return out
+}
+
+func mergeTask(a, b Task) Task {
+	fa, fb := fields(a), fields(b)
+	merged := map[string]json.RawMessage{}
+	clock := map[string]int64{}
+	keys := map[string]bool{}
+	for k := range fa {
+		keys[k] = true
+	}
+	for k := range fb {
+		keys[k] = true
+	}
+	for k := range a.Clock {
+		keys[k] = true
+	}
+	for k := range b.Clock {
+		keys[k] = true
+	}
+	for k := range keys {
+		ca, cb := a.Clock[k], b.Clock[k]
+		va, inA := fa[k]
+		vb, inB := fb[k]
+		useB := cb > ca || (cb == ca && bytes.Compare(vb, va) > 0)
+		if useB {
+			if inB {
+				merged[k] = vb
+			}
+		} else if inA {
+			merged[k] = va
+		}
+		if c := max(ca, cb); c > 0 {
+			clock[k] = c
+		}
+	}
+	t := fromFields(merged)
+	t.Clock = clock
+	return t
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// OnSave hooks that are slow, like committing to the history, do their
+// work off the UI goroutine and say how it went with Report. The last
+// failure of each shows in the help line until it next succeeds.
+
+// reports holds the outcomes not yet picked up by Update, by name. Only
+// the latest of each matters, so Report never has to wait for the UI.
+type reports struct {
+	mu      sync.Mutex
+	pending map[string]error
+	ready   chan struct{}
+}
+
+// reportMsg says there are outcomes to pick up.
+type reportMsg struct{}
+
+func (m *Model) reportsReady() chan struct{} {
+	m.reports.mu.Lock()
+	defer m.reports.mu.Unlock()
+	if m.reports.ready == nil {
+		m.reports.ready = make(chan struct{}, 1)
+	}
+	return m.reports.ready
+}
+
+// Report tells the model how background work called name went. It may
+// be called from any goroutine, before the program starts too.
+func (m *Model) Report(name string, err error) {
+	ready := m.reportsReady()
+	m.reports.mu.Lock()
+	if m.reports.pending == nil {
+		m.reports.pending = map[string]error{}
+	}
+	m.reports.pending[name] = err
+	m.reports.mu.Unlock()
+	select {
+	case ready <- struct{}{}:
+	default:
+	}
+}
+
+func (m *Model) waitForReport() tea.Cmd {
+	ready := m.reportsReady()
+	return func() tea.Msg {
+		<-ready
+		return reportMsg{}
+	}
+}
+
+// takeReports moves the pending outcomes into failures.
+func (m *Model) takeReports() {
+	m.reports.mu.Lock()
+	pending := m.reports.pending
+	m.reports.pending = nil
+	m.reports.mu.Unlock()
+	for name, err := range pending {
+		if err == nil {
+			delete(m.failures, name)
+			continue
+		}
+		if m.failures == nil {
+			m.failures = map[string]error{}
+		}
+		m.failures[name] = err
+	}
+}
+
+// failureStatus is the part of the help line for failed background work.
+func (m *Model) failureStatus() string {
+	var s strings.Builder
+	for _, name := range slices.Sorted(maps.Keys(m.failures)) {
+		// Errors from git and joined errors can run over several lines.
+		s.WriteString(" • " + name + " failed: " + strings.Join(strings.Fields(m.failures[name].Error()), " "))
+	}
+	return s.String()
+}
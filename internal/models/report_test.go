@@ -0,0 +1,30 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReportShowsFailuresUntilSuccess(t *testing.T) {
+	m := &Model{Width: 120, Height: 30}
+	wait := m.waitForReport()
+
+	m.Report("History", errors.New("git commit: index.lock exists\nanother git process seems to be running"))
+	m.Update(wait())
+	status := m.failureStatus()
+	if !strings.Contains(status, "History failed: git commit: index.lock exists another git") {
+		t.Errorf("status %q", status)
+	}
+	if !strings.Contains(m.View(), "History failed") {
+		t.Error("failure missing from the help line")
+	}
+
+	// Only the latest outcome counts, even if the UI was busy meanwhile.
+	m.Report("History", errors.New("again"))
+	m.Report("History", nil)
+	m.Update(m.waitForReport()())
+	if status := m.failureStatus(); status != "" {
+		t.Errorf("status %q after a success", status)
+	}
+}
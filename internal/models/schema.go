@@ -0,0 +1,9 @@
+package models
+
+import _ "embed"
+
+// Schema is the JSON Schema for AppData as saved in the data file, at
+// DataVersion.
+//
+//go:embed appdata.schema.json
+var Schema []byte
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// A task's Status places it on the board. Done stays the source of truth
+// for whether the task is finished, so writers that only know Done, such
+// as imports and other clients, can't leave the two disagreeing; see
+// Stage.
+const (
+	StatusTodo  = "todo"
+	StatusDoing = "doing"
+	StatusDone  = "done"
+)
+
+// Stage is the task's status as far as Done allows: a done task is
+// StatusDone, and an unfinished one is StatusDoing or StatusTodo.
+func (t Task) Stage() string {
+	switch {
+	case t.Done:
+		return StatusDone
+	case t.Status == StatusDoing:
+		return StatusDoing
+	}
+	return StatusTodo
+}
+
+// SetDone finishes the task or reopens it. Reopening puts it back in
+// StatusTodo.
+func (t *Task) SetDone(done bool, now time.Time) {
+	t.Done = done
+	t.CompletedAt = time.Time{}
+	t.Status = StatusTodo
+	if done {
+		t.CompletedAt = now
+		t.Status = StatusDone
+		t.StopTracking(now)
+	}
+}
+
+// SetStatus moves the task to status, finishing or reopening it as
+// needed.
+func (t *Task) SetStatus(status string, now time.Time) {
+	if (status == StatusDone) != t.Done {
+		t.SetDone(status == StatusDone, now)
+	}
+	t.Status = status
+}
@@ -2,15 +2,21 @@ package models
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
 	"time"
 
 	"github.com/nirabyte/todo/internal/config"
+	"github.com/nirabyte/todo/internal/control"
 )
 
-func LoadData() AppData {
-	data, err := os.ReadFile(config.DataFile)
-
+// LoadData reads the data file, or returns the hint tasks when there is
+// none yet. A file that can't be read is an error rather than an empty
+// list, so the first save doesn't overwrite it.
+func LoadData() (AppData, error) {
 	hints := []Task{
 		{ID: 1, Title: "Press 'n' to add a new task", Done: false},
 		{ID: 2, Title: "Press 'e' to edit the selected task", Done: false},
@@ -22,40 +28,151 @@ func LoadData() AppData {
 	}
 
 	defaultData := AppData{
+		Version:    DataVersion,
 		ThemeIndex: 0,
 		SortMode:   SortOff,
 		Tasks:      hints,
 	}
 
-	if err != nil {
-		return defaultData
+	appData, err := ReadData()
+	if errors.Is(err, os.ErrNotExist) {
+		return defaultData, nil
 	}
+	return appData, err
+}
 
+// ReadData reads the data file, reporting a missing or unreadable file
+// instead of falling back to the hints. Background writers use it so a
+// half-written file is never mistaken for an empty list. A file from an
+// older version is backed up, upgraded and written back; one from a newer
+// version is refused with ErrNewerVersion.
+func ReadData() (AppData, error) {
 	var appData AppData
-	if err := json.Unmarshal(data, &appData); err == nil {
-		for i := range appData.Tasks {
-			if appData.Tasks[i].ID == 0 {
-				appData.Tasks[i].ID = time.Now().UnixNano() + int64(i)
-			}
+	data, err := os.ReadFile(config.DataFile)
+	if err != nil {
+		return appData, err
+	}
+	version, err := fileVersion(data)
+	if err != nil {
+		return appData, fmt.Errorf("%s: %w", config.DataFile, err)
+	}
+	if version > DataVersion {
+		return appData, fmt.Errorf("%s has version %d but this todo reads up to version %d; upgrade todo: %w",
+			config.DataFile, version, DataVersion, ErrNewerVersion)
+	}
+	old := data
+	if version < DataVersion {
+		if data, err = migrate(data, version); err != nil {
+			return appData, fmt.Errorf("%s: %w", config.DataFile, err)
+		}
+	}
+	if err := json.Unmarshal(data, &appData); err != nil {
+		return appData, fmt.Errorf("%s: %w", config.DataFile, err)
+	}
+	for i := range appData.Tasks {
+		if appData.Tasks[i].ID == 0 {
+			appData.Tasks[i].ID = time.Now().UnixNano() + int64(i)
+		}
+	}
+	if version < DataVersion {
+		if _, err := backup(old, version); err != nil {
+			return appData, fmt.Errorf("back up %s before upgrading it: %w", config.DataFile, err)
+		}
+		if err := WriteData(appData); err != nil {
+			return appData, err
+		}
+	}
+	return appData, nil
+}
+
+// WriteData replaces the data file atomically, so the daemon and the TUI
+// never read each other's partial writes. With SyncClocks on, it stamps
+// what changed since the file it replaces.
+func WriteData(data AppData) error {
+	return WriteDataClocks(data, SyncClocks)
+}
+
+// WriteDataClocks is WriteData with the caller saying whether to stamp
+// clocks, for commands that don't go by SyncClocks.
+func WriteDataClocks(data AppData, clocks bool) error {
+	if clocks {
+		var old AppData
+		if raw, err := os.ReadFile(config.DataFile); err == nil {
+			json.Unmarshal(raw, &old)
 		}
-		return appData
+		Stamp(&old, &data, time.Now())
+	}
+	return ReplaceData(data)
+}
+
+// ReplaceData writes data as it is, clocks and all. Sync uses it to store
+// a merged list without stamping the other replica's changes as its own.
+func ReplaceData(data AppData) error {
+	data.Version = DataVersion
+	bytes, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(config.DataFile), ".todos-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(bytes); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
 	}
-	return defaultData
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), config.DataFile)
 }
 
 func (m *Model) Save() {
-	var validTasks []Task
+	var validTasks, deleting []Task
 	for _, t := range m.Tasks {
-		if !t.IsDeleting {
+		if t.IsDeleting {
+			deleting = append(deleting, t)
+		} else {
 			validTasks = append(validTasks, t)
 		}
 	}
+	changed := false
+	if m.base != nil {
+		// Other programs may have written the file since it was read.
+		if cur, err := ReadData(); err == nil {
+			validTasks, changed = rebase(m.base, validTasks, cur.Tasks)
+		}
+	}
+	if m.Notes != nil {
+		tasks, pulled, err := m.Notes(validTasks, m.now())
+		m.Report("Notes", err)
+		if pulled {
+			validTasks, changed = tasks, true
+		}
+	}
+	if changed {
+		m.adopt(append(slices.Clone(validTasks), deleting...))
+	}
 	data := AppData{
 		ThemeIndex: m.ThemeIndex,
 		SortMode:   m.SortMode,
 		Tasks:      validTasks,
+		GoalDay:    m.GoalDay,
+	}
+	err := WriteData(data)
+	m.Report("Save", err)
+	if err == nil {
+		m.remember(data.Tasks)
+	}
+	for _, hook := range m.OnSave {
+		hook(data)
+	}
+	if m.Daemon != "" {
+		// Best effort: the daemon also notices the change on its next poll.
+		go control.Send(m.Daemon, control.CmdReload)
 	}
-	bytes, _ := json.MarshalIndent(data, "", "  ")
-	os.WriteFile(config.DataFile, bytes, 0644)
 }
-
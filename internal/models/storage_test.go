@@ -0,0 +1,90 @@
+package models
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nirabyte/todo/internal/config"
+)
+
+func TestSavePullsNotesFirst(t *testing.T) {
+	t.Chdir(t.TempDir())
+	m := &Model{Tasks: []Task{{ID: 1, Title: "Buy milk"}, {ID: 2, Title: "Call mum", IsDeleting: true}}}
+	m.Notes = func(tasks []Task, now time.Time) ([]Task, bool, error) {
+		if len(tasks) != 1 {
+			t.Errorf("notes got %+v, want only the tasks being saved", tasks)
+		}
+		return append(tasks, Task{ID: 3, Title: "From the notes"}), true, nil
+	}
+	m.Save()
+
+	data, err := ReadData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Tasks) != 2 || data.Tasks[1].Title != "From the notes" {
+		t.Errorf("saved %+v", data.Tasks)
+	}
+	// The task being deleted stays until its animation ends.
+	if len(m.Tasks) != 3 {
+		t.Errorf("model has %+v", m.Tasks)
+	}
+}
+
+func TestSaveKeepsChangesMadeElsewhere(t *testing.T) {
+	t.Chdir(t.TempDir())
+	m := &Model{Tasks: []Task{{ID: 1, Title: "Buy milk"}, {ID: 2, Title: "Call mum"}, {ID: 3, Title: "Old"}}}
+	m.Save()
+
+	// Meanwhile another program ticks one task, deletes one and adds one.
+	if err := WriteData(AppData{Tasks: []Task{
+		{ID: 1, Title: "Buy milk", Done: true}, {ID: 2, Title: "Call mum"}, {ID: 4, Title: "From the API"},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	m.Tasks[0].Title = "Buy oat milk"
+	m.Save()
+
+	data, err := ReadData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Task{{ID: 1, Title: "Buy oat milk", Done: true}, {ID: 2, Title: "Call mum"}, {ID: 4, Title: "From the API"}}
+	if len(data.Tasks) != len(want) {
+		t.Fatalf("saved %+v", data.Tasks)
+	}
+	for i, w := range want {
+		if got := data.Tasks[i]; got.ID != w.ID || got.Title != w.Title || got.Done != w.Done {
+			t.Errorf("task %d = %+v, want %+v", i, got, w)
+		}
+	}
+	if len(m.Tasks) != 3 {
+		t.Errorf("model has %+v", m.Tasks)
+	}
+}
+
+func TestSaveReportsWriteFailure(t *testing.T) {
+	t.Chdir(t.TempDir())
+	// A directory where the data file should be can't be replaced.
+	if err := os.Mkdir(config.DataFile, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	m := &Model{Width: 120, Height: 30, Tasks: []Task{{ID: 1, Title: "Buy milk"}}}
+	wait := m.waitForReport()
+	m.Save()
+	m.Update(wait())
+	if status := m.failureStatus(); !strings.Contains(status, "Save failed") {
+		t.Errorf("status %q", status)
+	}
+
+	if err := os.Remove(config.DataFile); err != nil {
+		t.Fatal(err)
+	}
+	m.Save()
+	m.Update(m.waitForReport()())
+	if status := m.failureStatus(); status != "" {
+		t.Errorf("status %q after saving", status)
+	}
+}
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SyncMsg carries the result of a sync with the server.
+type SyncMsg struct {
+	Data AppData
+	Err  error
+}
+
+type syncDueMsg struct{}
+
+// syncCmd saves the list and sends it to the sync server in the
+// background. It does nothing while a sync is already running.
+func (m *Model) syncCmd() tea.Cmd {
+	if m.Sync == nil || m.syncing {
+		return nil
+	}
+	m.Save()
+	data, err := ReadData()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return func() tea.Msg { return SyncMsg{Err: err} }
+	}
+	m.syncQueued = pending(data)
+	m.syncing = true
+	sync := m.Sync
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		merged, err := sync(ctx, data)
+		return SyncMsg{Data: merged, Err: err}
+	}
+}
+
+// pending counts the changes made since the last successful sync.
+func pending(data AppData) int {
+	since := data.SyncedAt.UnixNano()
+	n := 0
+	for _, t := range data.Tasks {
+		if lastChange(t) > since {
+			n++
+		}
+	}
+	for _, ts := range data.Tombstones {
+		if ts.At.After(data.SyncedAt) {
+			n++
+		}
+	}
+	return n
+}
+
+func (m *Model) syncLater() tea.Cmd {
+	return tea.Tick(m.SyncInterval, func(time.Time) tea.Msg { return syncDueMsg{} })
+}
+
+// applySync stores what the server sent, merged once more with anything
+// saved while the request was out, and shows it. While a text input is
+// open the result is dropped, since the cursor is in use; the next sync
+// fetches it again.
+func (m *Model) applySync(data AppData) {
+	if m.prompting() {
+		return
+	}
+	cur, err := ReadData()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		m.syncErr = err
+		return
+	}
+	merged := Merge(cur, data)
+	merged.SyncedAt = data.SyncedAt
+	if err := ReplaceData(merged); err != nil {
+		m.syncErr = err
+		return
+	}
+	m.syncQueued = 0
+	m.adopt(merged.Tasks)
+	// Nothing differs from the file any more, so this only runs the hooks.
+	m.Save()
+}
+
+// adopt replaces the task list with one read from elsewhere, keeping the
+// cursor on the same task and animations that are under way.
+func (m *Model) adopt(tasks []Task) {
+	var selected int64
+	if m.Cursor < len(m.Tasks) {
+		selected = m.Tasks[m.Cursor].ID
+	}
+	prev := map[int64]Task{}
+	for _, t := range m.Tasks {
+		prev[t.ID] = t
+	}
+	for i := range tasks {
+		if p, ok := prev[tasks[i].ID]; ok {
+			tasks[i].IsAnimatingCheck = p.IsAnimatingCheck
+			tasks[i].IsDeleting = p.IsDeleting
+			tasks[i].AnimType = p.AnimType
+			tasks[i].AnimStart = p.AnimStart
+			tasks[i].AnimSeed = p.AnimSeed
+		}
+	}
+	m.Tasks = tasks
+	if m.SortMode != SortOff {
+		m.ApplySort()
+	}
+	for i, t := range m.Tasks {
+		if t.ID == selected {
+			m.Cursor = i
+		}
+	}
+	if m.Cursor >= len(m.Tasks) {
+		m.Cursor = max(len(m.Tasks)-1, 0)
+	}
+	if m.Focus != nil && m.focusTask() == nil {
+		m.stopFocus()
+	}
+}
+
+// syncStatus is the sync part of the help line.
+func (m *Model) syncStatus() string {
+	switch {
+	case m.Sync == nil:
+		return ""
+	case m.syncErr != nil && m.syncQueued > 0:
+		return fmt.Sprintf(" • Sync: offline, %d queued", m.syncQueued)
+	case m.syncErr != nil:
+		return " • Sync: offline"
+	case m.syncing:
+		return " • Sync: …"
+	}
+	return " • Sync: ok"
+}
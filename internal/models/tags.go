@@ -0,0 +1,48 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	tagPattern     = regexp.MustCompile(`(?:^|\s)#([\p{L}\p{N}_-]+)`)
+	projectPattern = regexp.MustCompile(`(?:^|\s)\+(\S+)`)
+	contextPattern = regexp.MustCompile(`(?:^|\s)@(\S+)`)
+)
+
+// SetTitle sets t's title and the tags, projects and contexts taken from it.
+func (t *Task) SetTitle(title string) {
+	t.Title = title
+	t.Tags = ParseTags(title)
+	t.Projects = parseWords(projectPattern, title)
+	t.Contexts = parseWords(contextPattern, title)
+}
+
+// ParseTags returns the #hashtags in a title, lowercased and without
+// duplicates, in the order they appear.
+func ParseTags(title string) []string {
+	var tags []string
+	seen := map[string]bool{}
+	for _, m := range tagPattern.FindAllStringSubmatch(title, -1) {
+		tag := strings.ToLower(m[1])
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// parseWords is ParseTags for the todo.txt markers, which keep their case.
+func parseWords(pattern *regexp.Regexp, title string) []string {
+	var words []string
+	seen := map[string]bool{}
+	for _, m := range pattern.FindAllStringSubmatch(title, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			words = append(words, m[1])
+		}
+	}
+	return words
+}
@@ -0,0 +1,244 @@
+package models
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nirabyte/todo/internal/config"
+	"github.com/nirabyte/todo/internal/control"
+	"github.com/nirabyte/todo/internal/notify"
+)
+
+// The model runs three independent timers so that an idle list costs
+// nothing: TickMsg at config.FPS while something is animating, SecondMsg
+// once a second while a countdown is on screen, and a single DueMsg armed
+// for the nearest reminder or end of a focus phase. With a sync server
+// configured, a fourth wakes it every SyncInterval (see sync.go).
+
+type SecondMsg struct{}
+
+// DueMsg fires at the nearest deadline. Gen tells a stale timer, armed
+// before the deadlines changed, from the current one.
+type DueMsg struct {
+	Gen int
+}
+
+func tickCmd() tea.Cmd {
+	return tea.Tick(time.Second/time.Duration(config.FPS), func(t time.Time) tea.Msg {
+		return TickMsg{}
+	})
+}
+
+func secondCmd() tea.Cmd {
+	return tea.Every(time.Second, func(t time.Time) tea.Msg {
+		return SecondMsg{}
+	})
+}
+
+// animate starts the frame ticker unless it is already running.
+func (m *Model) animate() tea.Cmd {
+	if m.animating {
+		return nil
+	}
+	m.animating = true
+	return tickCmd()
+}
+
+func (m *Model) hasCountdown() bool {
+	if m.Focus != nil && !m.Focus.Paused || m.isTracking() {
+		return true
+	}
+	now := m.now()
+	for _, t := range m.Tasks {
+		if !t.Done && (t.DueAt.After(now) || t.SnoozedUntil.After(now)) {
+			return true
+		}
+	}
+	return false
+}
+
+// scheduleTimers re-arms the countdown refresh and the deadline timer
+// after the tasks' due dates or completion changed.
+func (m *Model) scheduleTimers() tea.Cmd {
+	var cmds []tea.Cmd
+	if !m.counting && m.hasCountdown() {
+		m.counting = true
+		cmds = append(cmds, secondCmd())
+	}
+
+	m.dueGen++
+	var next time.Time
+	for _, t := range m.Tasks {
+		at := m.nextAlert(t)
+		if !at.IsZero() && (next.IsZero() || at.Before(next)) {
+			next = at
+		}
+	}
+	if f := m.Focus; f != nil && !f.Paused && (next.IsZero() || f.PhaseEnd.Before(next)) {
+		next = f.PhaseEnd
+	}
+	if !next.IsZero() {
+		gen := m.dueGen
+		cmds = append(cmds, tea.Tick(max(next.Sub(m.now()), 0), func(time.Time) tea.Msg {
+			return DueMsg{Gen: gen}
+		}))
+	}
+	return tea.Batch(cmds...)
+}
+
+// AlertRetry is how long a notification that failed to send waits before
+// it is tried again, in the app and the daemon alike.
+const AlertRetry = time.Minute
+
+// nextAlert is t's next alert, held back while one is being sent and,
+// after a failed send, until it is time to retry.
+func (m *Model) nextAlert(t Task) time.Time {
+	at := t.NextAlert(m.OverdueInterval)
+	hold, ok := m.alerting[t.ID]
+	switch {
+	case !ok || at.IsZero():
+		return at
+	case hold.IsZero():
+		return time.Time{}
+	case at.Before(hold):
+		return hold
+	}
+	return at
+}
+
+// AlertMsg reports how sending the alert for a task at At went. Daemon
+// means the daemon was running, so the app left the alert to it.
+type AlertMsg struct {
+	TaskID int64
+	At     time.Time
+	Err    error
+	Daemon bool
+}
+
+// daemonProbeAge is how long the app trusts its last look at whether the
+// daemon is running.
+const daemonProbeAge = 10 * time.Second
+
+// notifyDue sends the alerts that have come due. Each is recorded on its
+// task once it has gone out, so one that fails is retried. When the daemon
+// is running it owns the alert and the write to the data file, so the
+// model only marks the task in memory; saving first would tell the daemon
+// the alert had already gone out. Whether it runs is found out along with
+// the sending, off the UI goroutine.
+func (m *Model) notifyDue() tea.Cmd {
+	now := m.now()
+	daemon := m.daemonProbe(now)
+	var cmds []tea.Cmd
+	for i := range m.Tasks {
+		t := &m.Tasks[i]
+		at := m.nextAlert(*t)
+		if at.IsZero() || now.Before(at) {
+			continue
+		}
+		n := t.Notification(now)
+		id := t.ID
+		n.OnAction = func(key string) {
+			m.actions <- ActionMsg{TaskID: id, Key: key}
+		}
+		if m.alerting == nil {
+			m.alerting = map[int64]time.Time{}
+		}
+		m.alerting[id] = time.Time{}
+		// Webhooks and email can be slow; don't hold up the UI.
+		notifier := m.notifier()
+		cmds = append(cmds, func() tea.Msg {
+			if daemon() {
+				return AlertMsg{TaskID: id, At: now, Daemon: true}
+			}
+			return AlertMsg{TaskID: id, At: now, Err: notifier.Notify(n)}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// daemonProbe returns whether the daemon is running: the answer from the
+// last alert while it is recent, or else a probe shared by the alerts
+// sent now.
+func (m *Model) daemonProbe(now time.Time) func() bool {
+	switch {
+	case m.Daemon == "":
+		return func() bool { return false }
+	case !m.daemonAt.IsZero() && now.Sub(m.daemonAt) < daemonProbeAge:
+		up := m.daemonUp
+		return func() bool { return up }
+	}
+	socket := m.Daemon
+	return sync.OnceValue(func() bool { return control.Alive(socket) })
+}
+
+// alerted records how sending an alert went.
+func (m *Model) alerted(msg AlertMsg) {
+	if m.Daemon != "" {
+		m.daemonUp, m.daemonAt = msg.Daemon, m.now()
+	}
+	if msg.Daemon {
+		delete(m.alerting, msg.TaskID)
+		for i := range m.Tasks {
+			if t := &m.Tasks[i]; t.ID == msg.TaskID && t.LastAlert.Before(msg.At) {
+				t.MarkAlerted(msg.At)
+			}
+		}
+		return
+	}
+	m.alertErr = msg.Err
+	if msg.Err != nil {
+		m.alerting[msg.TaskID] = m.now().Add(AlertRetry)
+		return
+	}
+	delete(m.alerting, msg.TaskID)
+	for i := range m.Tasks {
+		// A deadline changed meanwhile has already moved LastAlert on.
+		if t := &m.Tasks[i]; t.ID == msg.TaskID && t.LastAlert.Before(msg.At) {
+			t.MarkAlerted(msg.At)
+			m.Save()
+		}
+	}
+}
+
+// alertStatus is the notification part of the help line, shown while
+// alerts fail to send.
+func (m *Model) alertStatus() string {
+	if m.alertErr == nil {
+		return ""
+	}
+	// Errors joined from several sinks come a line each.
+	return " • Alert failed: " + strings.ReplaceAll(m.alertErr.Error(), "\n", "; ")
+}
+
+func (m *Model) notifier() notify.Notifier {
+	if m.Notifier == nil {
+		return notify.Desktop{}
+	}
+	return m.Notifier
+}
+
+// ActionMsg is a button pressed on one of the task's notifications.
+type ActionMsg struct {
+	TaskID int64
+	Key    string
+}
+
+func waitForAction(ch chan ActionMsg) tea.Cmd {
+	return func() tea.Msg {
+		return <-ch
+	}
+}
+
+func (m *Model) applyAction(msg ActionMsg) {
+	for i := range m.Tasks {
+		if m.Tasks[i].ID == msg.TaskID {
+			if m.Tasks[i].ApplyAction(msg.Key, m.now()) == nil {
+				m.ApplySort()
+				m.Save()
+			}
+			return
+		}
+	}
+}
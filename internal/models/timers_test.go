@@ -0,0 +1,175 @@
+package models
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nirabyte/todo/internal/control"
+	"github.com/nirabyte/todo/internal/notify"
+)
+
+// runCmds runs cmd and any commands it batches, returning their messages.
+func runCmds(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		var msgs []tea.Msg
+		for _, c := range batch {
+			msgs = append(msgs, runCmds(c)...)
+		}
+		return msgs
+	}
+	if msg == nil {
+		return nil
+	}
+	return []tea.Msg{msg}
+}
+
+func TestNotifyDueRecordsSentAlerts(t *testing.T) {
+	t.Chdir(t.TempDir())
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	rec := &notify.Recorder{}
+	m := &Model{Clock: fixedClock{now}, Notifier: rec, actions: make(chan ActionMsg, 1), Tasks: []Task{
+		{ID: 1, Title: "Pay rent", DueAt: now.Add(-time.Minute), LastAlert: now.Add(-time.Hour)},
+		{ID: 2, Title: "Later", DueAt: now.Add(time.Hour), LastAlert: now},
+	}}
+
+	msgs := runCmds(m.notifyDue())
+	if len(msgs) != 1 {
+		t.Fatalf("got %d messages, want 1", len(msgs))
+	}
+	if !m.Tasks[0].LastAlert.Before(now) {
+		t.Error("alert recorded before it was sent")
+	}
+	if again := runCmds(m.notifyDue()); len(again) != 0 {
+		t.Errorf("alert sent again while the first was in flight")
+	}
+
+	m.alerted(msgs[0].(AlertMsg))
+	if sent := rec.Sent(); len(sent) != 1 || sent[0].TaskID != 1 {
+		t.Errorf("sent %+v", sent)
+	}
+	if !m.Tasks[0].LastAlert.Equal(now) || !m.Tasks[0].Notified {
+		t.Errorf("after sending: last alert %v, notified %v", m.Tasks[0].LastAlert, m.Tasks[0].Notified)
+	}
+	if m.alertStatus() != "" {
+		t.Errorf("status %q after a successful send", m.alertStatus())
+	}
+	data, err := ReadData()
+	if err != nil || !data.Tasks[0].LastAlert.Equal(now) {
+		t.Errorf("saved %+v, %v", data.Tasks, err)
+	}
+}
+
+func TestNotifyDueRetriesFailedAlerts(t *testing.T) {
+	t.Chdir(t.TempDir())
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	clock := &fixedClock{now}
+	rec := &notify.Recorder{}
+	rec.Fail(errors.New("webhook: 502 Bad Gateway"))
+	last := now.Add(-time.Hour)
+	m := &Model{Clock: clock, Notifier: rec, actions: make(chan ActionMsg, 1), Tasks: []Task{
+		{ID: 1, Title: "Pay rent", DueAt: now.Add(-time.Minute), LastAlert: last},
+	}}
+
+	for _, msg := range runCmds(m.notifyDue()) {
+		m.alerted(msg.(AlertMsg))
+	}
+	if !m.Tasks[0].LastAlert.Equal(last) || m.Tasks[0].Notified {
+		t.Errorf("failed alert recorded: %+v", m.Tasks[0])
+	}
+	if s := m.alertStatus(); !strings.Contains(s, "502 Bad Gateway") {
+		t.Errorf("status %q doesn't report the failure", s)
+	}
+	if at := m.nextAlert(m.Tasks[0]); !at.Equal(now.Add(AlertRetry)) {
+		t.Errorf("retry at %v, want %v", at, now.Add(AlertRetry))
+	}
+	if msgs := runCmds(m.notifyDue()); len(msgs) != 0 {
+		t.Errorf("retried before AlertRetry was up")
+	}
+
+	clock.t = now.Add(AlertRetry)
+	rec.Fail(nil)
+	for _, msg := range runCmds(m.notifyDue()) {
+		m.alerted(msg.(AlertMsg))
+	}
+	if len(rec.Sent()) != 1 || !m.Tasks[0].Notified {
+		t.Errorf("retry: sent %d, task %+v", len(rec.Sent()), m.Tasks[0])
+	}
+	if m.alertStatus() != "" {
+		t.Errorf("status %q after the retry went out", m.alertStatus())
+	}
+}
+
+func TestNotifyDueLeavesSendingToDaemon(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	socket := filepath.Join(dir, "daemon.sock")
+	l, err := control.Listen(socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go control.Serve(l, func(string) string { return "pong" })
+
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	rec := &notify.Recorder{}
+	m := &Model{Clock: fixedClock{now}, Notifier: rec, Daemon: socket, Tasks: []Task{
+		{ID: 1, Title: "Pay rent", DueAt: now.Add(-time.Minute), LastAlert: now.Add(-time.Hour)},
+	}}
+	msgs := runCmds(m.notifyDue())
+	for _, msg := range msgs {
+		m.Update(msg)
+	}
+	if len(msgs) != 1 || !msgs[0].(AlertMsg).Daemon || len(rec.Sent()) != 0 {
+		t.Errorf("the app sent the alert itself: %+v", msgs)
+	}
+	if !m.Tasks[0].LastAlert.Equal(now) {
+		t.Errorf("alert not marked in memory: %v", m.Tasks[0].LastAlert)
+	}
+	if _, err := ReadData(); err == nil {
+		t.Error("the app saved over the daemon")
+	}
+
+	// The answer is kept for a while, then the daemon is asked again.
+	l.Close()
+	m.Tasks[0].LastAlert, m.Tasks[0].Notified = now.Add(-time.Hour), false
+	if msgs := runCmds(m.notifyDue()); len(msgs) != 1 || !msgs[0].(AlertMsg).Daemon {
+		t.Errorf("probed again at once: %+v", msgs)
+	}
+	delete(m.alerting, 1)
+	m.Clock = fixedClock{now.Add(daemonProbeAge)}
+	if msgs := runCmds(m.notifyDue()); len(msgs) != 1 || msgs[0].(AlertMsg).Daemon || len(rec.Sent()) != 1 {
+		t.Errorf("the app didn't take over from a stopped daemon: %+v", msgs)
+	}
+}
+
+func TestFocusAlertFailureIsReported(t *testing.T) {
+	t.Chdir(t.TempDir())
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	rec := &notify.Recorder{}
+	rec.Fail(errors.New("dbus: no session bus"))
+	m := &Model{
+		Width: 120, Height: 30, Clock: fixedClock{now}, Notifier: rec,
+		FocusSettings: FocusSettings{Work: 25 * time.Minute, ShortBreak: 5 * time.Minute},
+		Tasks:         []Task{{ID: 1, Title: "Write report"}},
+	}
+	m.startFocus(m.Tasks[0])
+	m.Clock = fixedClock{now.Add(25 * time.Minute)}
+	m.advanceFocus(false)
+	// The save reports too, and may be picked up first.
+	for range 2 {
+		if m.Update(m.waitForReport()()); m.failureStatus() != "" {
+			break
+		}
+	}
+	if status := m.failureStatus(); !strings.Contains(status, "Focus alert failed: dbus: no session bus") {
+		t.Errorf("status %q", status)
+	}
+}
@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// Tracking reports whether a timer is running on t.
+func (t Task) Tracking() bool {
+	n := len(t.Tracked)
+	return n > 0 && t.Tracked[n-1].End.IsZero()
+}
+
+// TrackedTotal is the time tracked on t, counting a running timer up to now.
+func (t Task) TrackedTotal(now time.Time) time.Duration {
+	var total time.Duration
+	for _, i := range t.Tracked {
+		if i.End.IsZero() {
+			i.End = now
+		}
+		total += i.Duration()
+	}
+	return total
+}
+
+// StartTracking opens a new interval on t unless one is already running.
+func (t *Task) StartTracking(now time.Time) {
+	if !t.Tracking() {
+		t.Tracked = append(t.Tracked, Interval{Start: now})
+	}
+}
+
+// StopTracking closes t's running interval, if any.
+func (t *Task) StopTracking(now time.Time) {
+	if t.Tracking() {
+		t.Tracked[len(t.Tracked)-1].End = now
+	}
+}
+
+// toggleTracking starts the timer on the task under the cursor, stopping
+// any other so only one task is tracked at a time, or stops it if it was
+// already running.
+func (m *Model) toggleTracking() {
+	now := m.now()
+	t := &m.Tasks[m.Cursor]
+	if t.Tracking() {
+		t.StopTracking(now)
+		return
+	}
+	for i := range m.Tasks {
+		m.Tasks[i].StopTracking(now)
+	}
+	t.StartTracking(now)
+}
+
+func (m *Model) isTracking() bool {
+	for _, t := range m.Tasks {
+		if t.Tracking() {
+			return true
+		}
+	}
+	return false
+}
@@ -1,25 +1,22 @@
 package models
 
 import (
-	"math/rand"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/gen2brain/beeep"
 	"github.com/nirabyte/todo/internal/config"
 	"github.com/nirabyte/todo/internal/styles"
 	"github.com/nirabyte/todo/internal/themes"
 )
 
 func (m *Model) Init() tea.Cmd {
-	return textinput.Blink
-}
-
-func tickCmd() tea.Cmd {
-	return tea.Tick(time.Second/time.Duration(config.FPS), func(t time.Time) tea.Msg {
-		return TickMsg{}
-	})
+	if m.base == nil {
+		m.remember(m.Tasks)
+	}
+	m.actions = make(chan ActionMsg, 8)
+	return tea.Batch(textinput.Blink, m.scheduleTimers(), waitForAction(m.actions), m.waitForReport(), m.syncCmd(), m.listen())
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -28,18 +25,55 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// Any key dismisses a celebration early, and does nothing else
+		// so a key pressed to get rid of it can't change the list.
+		if m.Celebration != nil && msg.String() != "ctrl+c" {
+			m.Celebration = nil
+			return m, nil
+		}
+		m.Celebration = nil
+
+		if m.State == StateFocus {
+			switch msg.String() {
+			case "ctrl+c":
+				m.Save()
+				return m, tea.Quit
+			case "esc", "q", "f":
+				m.stopFocus()
+			case " ":
+				m.toggleFocusPause()
+				return m, m.scheduleTimers()
+			case "n":
+				m.advanceFocus(true)
+				return m, m.scheduleTimers()
+			}
+			return m, nil
+		}
+
+		if m.State == StateBoard {
+			return m, m.updateBoard(msg.String())
+		}
+		if m.State == StateAgenda {
+			return m, m.updateAgenda(msg.String())
+		}
+		if m.State == StateCalendar {
+			return m, m.updateCalendar(msg.String())
+		}
+
+		if m.State == StatePickingLink {
+			return m, m.updatePicking(msg.String())
+		}
 
-		if m.State == StateEditing || m.State == StateCreating || m.State == StateSettingTime {
+		if m.State != StateBrowse {
 			switch msg.String() {
 			case "enter":
 				val := m.TextInput.Value()
 
 				if m.State == StateSettingTime {
 					if val != "" {
-						dur, err := time.ParseDuration(val)
+						dur, err := config.ParseDuration(val)
 						if err == nil {
-							m.Tasks[m.Cursor].DueAt = time.Now().Add(dur)
-							m.Tasks[m.Cursor].Notified = false // Reset notification
+							m.Tasks[m.Cursor].SetDue(m.now().Add(dur), m.now())
 						} else {
 							m.Tasks[m.Cursor].DueAt = time.Time{}
 						}
@@ -49,8 +83,27 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.Save()
 					m.State = StateBrowse
 					m.TextInput.Blur()
-					// Jumpstart ticker for timer updates
-					return m, tickCmd()
+					return m, m.scheduleTimers()
+				}
+
+				if m.State == StateSettingReminders {
+					if offsets, err := ParseReminders(val); err == nil {
+						m.Tasks[m.Cursor].Reminders = offsets
+						m.Tasks[m.Cursor].LastAlert = m.now()
+						m.Save()
+					}
+					m.State = StateBrowse
+					m.TextInput.Blur()
+					return m, m.scheduleTimers()
+				}
+
+				if m.State == StateSnoozing {
+					if val != "" && m.Tasks[m.Cursor].Snooze(val, m.now()) == nil {
+						m.Save()
+					}
+					m.State = StateBrowse
+					m.TextInput.Blur()
+					return m, m.scheduleTimers()
 				}
 
 				if val == "" {
@@ -60,10 +113,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 
 				if m.State == StateCreating {
-					m.Tasks = append(m.Tasks, Task{
-						ID:    time.Now().UnixNano(),
-						Title: val,
-					})
+					task := Task{ID: m.now().UnixNano(), CreatedAt: m.now()}
+					task.SetTitle(val)
+					m.Tasks = append(m.Tasks, task)
 					if m.SortMode != SortOff {
 						m.ApplySort()
 					}
@@ -71,11 +123,16 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.State = StateBrowse
 					m.TextInput.Blur()
 					if m.SortMode == SortOff {
-						m.Cursor = len(m.Tasks) - 1
+						// Saving may have brought in tasks added elsewhere.
+						for i, t := range m.Tasks {
+							if t.ID == task.ID {
+								m.Cursor = i
+							}
+						}
 					}
 					return m, nil
 				} else {
-					m.Tasks[m.Cursor].Title = val
+					m.Tasks[m.Cursor].SetTitle(val)
 					m.Save()
 					m.State = StateBrowse
 					m.TextInput.Blur()
@@ -104,10 +161,22 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.Cursor < len(m.Tasks)-1 {
 				m.Cursor++
 			}
+		case "g", "home":
+			m.Cursor = 0
+		case "G", "end":
+			m.moveCursor(len(m.Tasks))
+		case "ctrl+u":
+			m.moveCursor(-m.listHeight() / 2)
+		case "ctrl+d":
+			m.moveCursor(m.listHeight() / 2)
+		case "pgup":
+			m.moveCursor(-m.listHeight())
+		case "pgdown":
+			m.moveCursor(m.listHeight())
 
 		case "t":
 			m.ThemeIndex = (m.ThemeIndex + 1) % len(themes.All)
-			styles.Update(themes.All[m.ThemeIndex])
+			styles.Update(themes.All[m.ThemeIndex], m.Accessible)
 			m.Save()
 
 		case "s":
@@ -115,6 +184,14 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.ApplySort()
 			m.Save()
 
+		case "b":
+			if len(m.Tasks) > 0 {
+				m.openBoard()
+			}
+
+		case "a":
+			m.State = StateAgenda
+
 		case "n":
 			m.State = StateCreating
 			m.TextInput.Placeholder = "Task name..."
@@ -141,39 +218,91 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, textinput.Blink
 			}
 
-		case "d":
+		case "r":
+			if len(m.Tasks) > 0 {
+				m.State = StateSettingReminders
+				m.TextInput.Placeholder = "e.g. 1d, 1h, 0"
+				m.TextInput.SetValue(FormatReminders(m.Tasks[m.Cursor].Reminders))
+				m.TextInput.Focus()
+				m.TextInput.SetCursor(len(m.TextInput.Value()))
+				return m, textinput.Blink
+			}
+
+		case "z":
 			if len(m.Tasks) > 0 {
+				m.State = StateSnoozing
+				m.TextInput.Placeholder = strings.Join(SnoozeChoices, ", ")
+				m.TextInput.SetValue("")
+				m.TextInput.Focus()
+				return m, textinput.Blink
+			}
+
+		case "x":
+			if len(m.Tasks) > 0 {
+				m.toggleTracking()
+				m.Save()
+				return m, m.scheduleTimers()
+			}
+
+		case "o":
+			return m, m.openLink()
+
+		case "y":
+			return m, m.copyLink()
+
+		case "f":
+			if len(m.Tasks) > 0 {
+				m.startFocus(m.Tasks[m.Cursor])
+				return m, m.scheduleTimers()
+			}
+
+		case "d":
+			if len(m.Tasks) > 0 && m.Accessible {
+				m.removeTask(m.Cursor)
+			} else if len(m.Tasks) > 0 {
 				m.Tasks[m.Cursor].IsDeleting = true
-				m.Tasks[m.Cursor].AnimStart = time.Now()
-				cmds = append(cmds, tickCmd())
+				m.Tasks[m.Cursor].AnimStart = m.now()
+				m.Tasks[m.Cursor].AnimSeed = m.rng().Int63()
+				cmds = append(cmds, m.animate())
 			}
 
 		case " ", "enter":
 			if len(m.Tasks) > 0 {
 				t := &m.Tasks[m.Cursor]
-				t.Done = !t.Done
+				t.SetDone(!t.Done, m.now())
 
-				if t.Done {
+				if t.Done && !m.Accessible {
 					t.IsAnimatingCheck = true
-					t.AnimStart = time.Now()
+					t.AnimStart = m.now()
+					t.AnimSeed = m.rng().Int63()
 
 					// Force Unique Random Animation
-					newAnim := rand.Intn(AnimCount)
+					newAnim := m.rng().Intn(AnimCount)
 					for newAnim == m.LastAnim {
-						newAnim = rand.Intn(AnimCount)
+						newAnim = m.rng().Intn(AnimCount)
 					}
 					t.AnimType = newAnim
 					m.LastAnim = newAnim
 
-					cmds = append(cmds, tickCmd())
+					cmds = append(cmds, m.animate())
 				} else {
 					t.IsAnimatingCheck = false
 				}
+				if t.Done {
+					m.celebrate()
+					if m.Celebration != nil {
+						cmds = append(cmds, m.animate())
+					}
+				}
 				m.ApplySort()
 				m.Save()
+				cmds = append(cmds, m.scheduleTimers())
 			}
 		}
 
+	case tea.MouseMsg:
+		return m.updateMouse(msg)
+
 	case tea.WindowSizeMsg:
 		m.Width = msg.Width
 		m.Height = msg.Height
@@ -181,43 +310,91 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case TickMsg:
 		needsTick := false
+		now := m.now()
 		for i := len(m.Tasks) - 1; i >= 0; i-- {
 			t := &m.Tasks[i]
 
-			// Animations
 			if t.IsDeleting {
-				if time.Since(t.AnimStart) > config.DeleteAnimDuration {
-					m.Tasks = append(m.Tasks[:i], m.Tasks[i+1:]...)
-					if m.Cursor >= len(m.Tasks) && m.Cursor > 0 {
-						m.Cursor--
-					}
-					m.Save()
-				} else {
-					needsTick = true
+				if now.Sub(t.AnimStart) > config.DeleteAnimDuration {
+					m.removeTask(i)
+					continue
 				}
+				needsTick = true
 			}
 			if t.IsAnimatingCheck {
-				if time.Since(t.AnimStart) > config.CheckAnimDuration {
+				if now.Sub(t.AnimStart) > config.CheckAnimDuration {
 					t.IsAnimatingCheck = false
 				} else {
 					needsTick = true
 				}
 			}
-			// Timer updates
-			if !t.Done && !t.DueAt.IsZero() {
+		}
+		if m.Celebration != nil {
+			if now.Sub(m.Celebration.Start) > config.CelebrationDuration {
+				m.Celebration = nil
+			} else {
 				needsTick = true
-				if time.Now().After(t.DueAt) && !t.Notified {
-					beeep.Notify("Todo Alert!", t.Title, "")
-					t.Notified = true
-					m.Save()
-				}
 			}
 		}
 		if needsTick {
 			cmds = append(cmds, tickCmd())
+		} else {
+			m.animating = false
+		}
+
+	case SecondMsg:
+		// Nothing to update: receiving the message re-renders the countdowns.
+		if m.hasCountdown() {
+			cmds = append(cmds, secondCmd())
+		} else {
+			m.counting = false
+		}
+
+	case ActionMsg:
+		m.applyAction(msg)
+		cmds = append(cmds, m.scheduleTimers(), waitForAction(m.actions))
+
+	case SyncMsg:
+		m.syncing = false
+		m.syncErr = msg.Err
+		if msg.Err == nil {
+			m.applySync(msg.Data)
+			cmds = append(cmds, m.scheduleTimers())
 		}
+		cmds = append(cmds, m.syncLater())
+
+	case ReloadMsg:
+		cmds = append(cmds, m.reload(), waitForReload(m.reloads))
+
+	case reloadRetryMsg:
+		cmds = append(cmds, m.reload())
+
+	case syncDueMsg:
+		cmds = append(cmds, m.syncCmd())
+
+	case DueMsg:
+		if msg.Gen == m.dueGen {
+			cmds = append(cmds, m.notifyDue())
+			m.advanceFocus(false)
+			cmds = append(cmds, m.scheduleTimers())
+		}
+
+	case reportMsg:
+		m.takeReports()
+		cmds = append(cmds, m.waitForReport())
+
+	case AlertMsg:
+		m.alerted(msg)
+		cmds = append(cmds, m.scheduleTimers())
 	}
 
 	return m, tea.Batch(cmds...)
 }
 
+func (m *Model) removeTask(i int) {
+	m.Tasks = append(m.Tasks[:i], m.Tasks[i+1:]...)
+	if m.Cursor >= len(m.Tasks) && m.Cursor > 0 {
+		m.Cursor--
+	}
+	m.Save()
+}
@@ -6,25 +6,55 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/nirabyte/todo/internal/styles"
 	"github.com/nirabyte/todo/internal/themes"
 )
 
 func (m *Model) View() string {
+	return m.scanZones(m.view())
+}
+
+func (m *Model) view() string {
 	currentTheme := themes.All[m.ThemeIndex]
-	var content string
+	if m.Celebration != nil {
+		return m.viewCelebration(currentTheme)
+	}
+	if m.State == StateFocus && m.Focus != nil {
+		return m.viewFocus(currentTheme)
+	}
 
-	content = m.viewList(currentTheme)
+	// The help bar wraps on narrow screens, so it goes first: the list
+	// gets whatever height it leaves.
+	status := m.viewHelp(currentTheme)
+	m.helpHeight = lipgloss.Height(status)
 
+	content := m.viewList(currentTheme)
 	header := styles.HeaderStyle.Render("// TODO LIST")
+	switch m.State {
+	case StateBoard:
+		content = m.viewBoard(currentTheme)
+		header = styles.HeaderStyle.Render("// TODO BOARD")
+	case StateAgenda:
+		content = m.viewAgenda(currentTheme)
+		header = styles.HeaderStyle.Render("// AGENDA")
+	case StateCalendar:
+		content = m.viewCalendar(currentTheme)
+		header = styles.HeaderStyle.Render("// CALENDAR")
+	}
 
 	container := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(currentTheme.Accent).
-		Width(min(m.Width-4, 100)).
-		Height(m.Height - 7).
+		Width(m.layout().width).
+		Height(m.listHeight()).
 		Render(content)
 
+	ui := lipgloss.JoinVertical(lipgloss.Center, header, container, status)
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, ui)
+}
+
+func (m *Model) viewHelp(t themes.Theme) string {
 	sortStr := "Off"
 	if m.SortMode == SortTodoFirst {
 		sortStr = "Todo"
@@ -33,18 +63,88 @@ func (m *Model) View() string {
 		sortStr = "Done"
 	}
 
-	help := fmt.Sprintf("Theme: %s (t) • Sort: %s (s) • New (n) • Edit (e) • Check (Space) • Notify (@) • Del (d)", currentTheme.Name, sortStr)
-	status := styles.HelpStyle.Render(help)
+	// Each help item doubles as a button for its key. Compact mode keeps
+	// only the key and a word.
+	items := []struct{ label, short, key string }{
+		{fmt.Sprintf("Theme: %s (t)", t.Name), "t theme", "t"},
+		{fmt.Sprintf("Sort: %s (s)", sortStr), "s sort", "s"},
+		{"New (n)", "n new", "n"},
+		{"Edit (e)", "e edit", "e"},
+		{"Check (Space)", "␣ check", " "},
+		{"Notify (@)", "@ notify", "@"},
+		{"Remind (r)", "r remind", "r"},
+		{"Snooze (z)", "z snooze", "z"},
+		{"Focus (f)", "f focus", "f"},
+		{"Track (x)", "x track", "x"},
+		{"Open (o)", "o open", "o"},
+		{"Copy (y)", "y copy", "y"},
+		{"Del (d)", "d del", "d"},
+		{"Board (b)", "b board", "b"},
+		{"Agenda (a)", "a agenda", "a"},
+	}
+	switch {
+	case m.State == StateAgenda:
+		items = []struct{ label, short, key string }{
+			{"List (a)", "a list", "a"},
+			{"Calendar (Tab)", "⇥ calendar", "tab"},
+			{"Day earlier (H)", "H -1d", "H"},
+			{"Day later (L)", "L +1d", "L"},
+			{"Week earlier (K)", "K -1w", "K"},
+			{"Week later (J)", "J +1w", "J"},
+		}
+	case m.State == StateCalendar && m.calPick:
+		items = []struct{ label, short, key string }{
+			{"Back (Esc)", "esc back", "esc"},
+			{"Day earlier (H)", "H -1d", "H"},
+			{"Day later (L)", "L +1d", "L"},
+			{"Week earlier (K)", "K -1w", "K"},
+			{"Week later (J)", "J +1w", "J"},
+		}
+	case m.State == StateCalendar:
+		items = []struct{ label, short, key string }{
+			{"List (a)", "a list", "a"},
+			{"Agenda (Tab)", "⇥ agenda", "tab"},
+			{"Prev month ([)", "[ prev", "["},
+			{"Next month (])", "] next", "]"},
+			{"Today (.)", ". today", "."},
+			{"Pick task (Enter)", "⏎ pick", "enter"},
+		}
+	case m.State == StateBoard:
+		items = []struct{ label, short, key string }{
+			{"List (b)", "b list", "b"},
+			{fmt.Sprintf("Columns: %s (c)", m.grouping()), "c columns", "c"},
+			{"Prev column (h)", "h prev", "h"},
+			{"Next column (l)", "l next", "l"},
+			{"Move left (H)", "H left", "H"},
+			{"Move right (L)", "L right", "L"},
+		}
+	}
+	compact := m.layout().compact
+	buttons := make([]string, len(items))
+	for i, item := range items {
+		label := item.label
+		if compact {
+			label = item.short
+		}
+		// Non-breaking spaces keep an item on one line when the bar wraps.
+		buttons[i] = m.zone(target{Key: item.key}, strings.ReplaceAll(label, " ", "\u00a0"))
+	}
+	help := strings.Join(buttons, " • ") + m.syncStatus() + m.alertStatus() + m.failureStatus()
+	return styles.HelpStyle.Width(max(m.Width-2, 10)).Align(lipgloss.Center).Render(help)
+}
 
-	ui := lipgloss.JoinVertical(lipgloss.Center, header, container, status)
-	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, ui)
+// listRow is a task row's parts before they are laid out.
+type listRow struct {
+	check string
+	title string // empty while the row is a text input
+	timer string
+	task  *Task
 }
 
 func (m *Model) viewList(t themes.Theme) string {
 	if len(m.Tasks) == 0 && m.State != StateCreating {
 		return styles.HelpStyle.Padding(2).Render("No tasks.")
 	}
-	var s strings.Builder
 
 	count := len(m.Tasks)
 	if m.State == StateCreating {
@@ -52,14 +152,85 @@ func (m *Model) viewList(t themes.Theme) string {
 	}
 	creatingIndex := len(m.Tasks)
 
-	// Layout Calc: Window - Borders(2) - Number(4) - Icon(3) - Timer(approx 25) - Spacers(6)
-	availableWidth := min(m.Width-4, 100)
-	textWidth := availableWidth - 40 // Give extra room for timer
+	now := m.now()
+	l := m.layout()
+	iconWidth := 3
+	if m.Accessible {
+		iconWidth = 6 // "[todo]" / "[done]"
+	}
+
+	// First pass: the parts of every row. The timer column is as wide as
+	// its widest entry, so it costs titles nothing when no task has one.
+	parts := make([]listRow, count)
+	timerWidth := 0
+	for i := range parts {
+		r := &parts[i]
+		isEditingThis := (m.State == StateEditing && i == m.Cursor)
+		isCreatingThis := (m.State == StateCreating && i == creatingIndex)
+		isSettingTime := (m.State == StateSettingTime || m.State == StateSettingReminders || m.State == StateSnoozing) && i == m.Cursor
+
+		if isEditingThis || isCreatingThis {
+			r.check = lipgloss.NewStyle().Foreground(t.Accent).Render(">")
+			continue
+		}
+		task := &m.Tasks[i]
+		r.task = task
+
+		switch {
+		case m.Accessible && task.Done:
+			r.check = lipgloss.NewStyle().Foreground(t.Success).Render("[done]")
+		case m.Accessible:
+			r.check = lipgloss.NewStyle().Foreground(t.Accent).Render("[todo]")
+		case task.Done:
+			r.check = lipgloss.NewStyle().Foreground(t.Success).Render("[✔]")
+		default:
+			r.check = lipgloss.NewStyle().Foreground(t.Accent).Render("[ ]")
+		}
+
+		if task.IsDeleting {
+			r.title = renderDeleteAnim(*task, t, now)
+		} else if task.IsAnimatingCheck {
+			r.title = renderCheckAnim(*task, t, now)
+		} else if task.Done {
+			r.title = renderTitle(*task, styles.StrikeStyle)
+		} else {
+			r.title = renderTitle(*task, lipgloss.NewStyle().Foreground(t.Fg))
+		}
+
+		if isSettingTime {
+			m.TextInput.Width = 20
+			r.timer = styles.InlineInputStyle.Render(m.TextInput.View())
+		} else {
+			r.timer = m.viewTimer(*task, now, l.compact)
+		}
+		timerWidth = max(timerWidth, lipgloss.Width(r.timer))
+	}
+
+	// What is left of the row for the title: the selection marker or
+	// padding (2), the number, the check box, the extra columns, the timer
+	// and the scroll bar (1).
+	leftWidth := iconWidth + 1
+	if !l.compact {
+		leftWidth += 5
+	}
+	room := l.width - 3 - leftWidth
+	if l.wide {
+		room -= wideColumnsWidth
+	}
+	textWidth := room
+	if timerWidth > 0 {
+		textWidth -= timerWidth + 2
+	}
 	if textWidth < 10 {
-		textWidth = 10
+		// Too narrow for both: the title keeps ten columns and the timer
+		// is cut to what is left.
+		textWidth = min(10, room)
+		timerWidth = max(room-textWidth-2, 0)
 	}
 
-	for i := 0; i < count; i++ {
+	// Second pass: lay them out.
+	var rows []string
+	for i, r := range parts {
 		selected := false
 		if m.State == StateCreating {
 			if i == creatingIndex {
@@ -71,76 +242,79 @@ func (m *Model) viewList(t themes.Theme) string {
 			}
 		}
 
-		numberStr := fmt.Sprintf("%d.", i+1)
-		var checkIcon string
 		var titleContent string
-		var dueContent string
-
-		isEditingThis := (m.State == StateEditing && i == m.Cursor)
-		isCreatingThis := (m.State == StateCreating && i == creatingIndex)
-		isSettingTime := (m.State == StateSettingTime && i == m.Cursor)
-
-		if isEditingThis || isCreatingThis {
-			checkIcon = lipgloss.NewStyle().Foreground(t.Accent).Render(">")
+		if r.task == nil {
 			m.TextInput.Width = textWidth
 			titleContent = styles.InlineInputStyle.Render(m.TextInput.View())
 		} else {
-			task := m.Tasks[i]
-
-			if task.Done {
-				checkIcon = lipgloss.NewStyle().Foreground(t.Success).Render("[✔]")
-			} else {
-				checkIcon = lipgloss.NewStyle().Foreground(t.Accent).Render("[ ]")
-			}
+			titleContent = hangingIndent(r.title, textWidth)
+		}
 
-			var rawTitle string
-			if task.IsDeleting {
-				rawTitle = renderDeleteAnim(task.Title, t)
-			} else if task.IsAnimatingCheck {
-				rawTitle = renderCheckAnim(task, t)
-			} else if task.Done {
-				rawTitle = styles.StrikeStyle.Render(task.Title)
-			} else {
-				rawTitle = lipgloss.NewStyle().Foreground(t.Fg).Render(task.Title)
-			}
+		checkIcon := r.check
+		if i < len(m.Tasks) {
+			checkIcon = m.zone(target{Row: i, Check: true}, checkIcon)
+		}
+		left := []string{lipgloss.NewStyle().Width(iconWidth).Align(lipgloss.Center).Render(checkIcon), " "}
+		if !l.compact {
+			numberStr := fmt.Sprintf("%d.", i+1)
+			left = append([]string{lipgloss.NewStyle().Foreground(t.Dim).Width(4).Align(lipgloss.Right).Render(numberStr), " "}, left...)
+		}
+		leftBlock := lipgloss.JoinHorizontal(lipgloss.Top, left...)
 
-			titleContent = lipgloss.NewStyle().Width(textWidth).Render(rawTitle)
-
-			if isSettingTime {
-				m.TextInput.Width = 20
-				dueContent = styles.InlineInputStyle.Render(m.TextInput.View())
-			} else if !task.DueAt.IsZero() && !task.Done {
-				timeRemaining := time.Until(task.DueAt)
-				if timeRemaining < 0 {
-					dueContent = styles.OverdueStyle.Render("[OVERDUE]")
-				} else {
-					dueContent = styles.DueStyle.Render(shortDur(timeRemaining))
-				}
-			}
+		cells := []string{leftBlock, titleContent}
+		if l.wide && r.task != nil {
+			cells = append(cells, viewColumns(*r.task))
+		}
+		if r.timer != "" && timerWidth > 0 {
+			cells = append(cells, "  ", ansi.Truncate(r.timer, timerWidth, "…"))
 		}
+		row := lipgloss.JoinHorizontal(lipgloss.Top, cells...)
 
-		leftBlock := lipgloss.JoinHorizontal(lipgloss.Top,
-			lipgloss.NewStyle().Foreground(t.Dim).Width(4).Align(lipgloss.Right).Render(numberStr),
-			" ",
-			lipgloss.NewStyle().Width(3).Align(lipgloss.Center).Render(checkIcon),
-			" ",
-		)
+		if m.State == StatePickingLink && i == m.Cursor {
+			picker := styles.HelpStyle.Render(m.viewPicking(textWidth))
+			row = lipgloss.JoinVertical(lipgloss.Left, row, lipgloss.NewStyle().PaddingLeft(lipgloss.Width(leftBlock)).Render(picker))
+		}
 
-		row := lipgloss.JoinHorizontal(lipgloss.Top,
-			leftBlock,
-			titleContent,
-			"   ",
-			dueContent,
-		)
+		if i < len(m.Tasks) {
+			row = m.zone(target{Row: i}, row)
+		}
 
 		if selected {
-			s.WriteString(styles.ListSelectedStyle.Render(row))
+			rows = append(rows, styles.ListSelectedStyle.Render(row))
 		} else {
-			s.WriteString(styles.ListItemStyle.Render(row))
+			rows = append(rows, styles.ListItemStyle.Render(row))
 		}
-		s.WriteString("\n")
 	}
-	return s.String()
+	focus := m.Cursor
+	if m.State == StateCreating {
+		focus = creatingIndex
+	}
+	return m.viewport(t, rows, focus)
+}
+
+// viewTimer is the countdown or running total shown at the end of a row.
+func (m *Model) viewTimer(task Task, now time.Time, compact bool) string {
+	dur := shortDur
+	if compact {
+		dur = compactDur
+	}
+	if task.Tracking() {
+		return styles.TrackingStyle.Render("● " + dur(task.TrackedTotal(now)))
+	}
+	if task.SnoozedUntil.After(now) && !task.Done {
+		return styles.DueStyle.Render("zZ " + dur(task.SnoozedUntil.Sub(now)))
+	}
+	if task.DueAt.IsZero() || task.Done {
+		return ""
+	}
+	timeRemaining := task.DueAt.Sub(now)
+	switch {
+	case timeRemaining < 0 && m.Accessible:
+		return styles.OverdueStyle.Render("[overdue]")
+	case timeRemaining < 0:
+		return styles.OverdueStyle.Render("[OVERDUE]")
+	}
+	return styles.DueStyle.Render(dur(timeRemaining))
 }
 
 func shortDur(d time.Duration) string {
@@ -164,4 +338,3 @@ func min(a, b int) int {
 	}
 	return b
 }
-
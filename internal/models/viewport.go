@@ -0,0 +1,106 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nirabyte/todo/internal/styles"
+	"github.com/nirabyte/todo/internal/themes"
+)
+
+// listHeight is how many lines the list container has inside its border:
+// the window less the header (2), the border (2), the help bar and a line
+// of margin above and below.
+func (m *Model) listHeight() int {
+	return max(m.Height-6-max(m.helpHeight, 1), 1)
+}
+
+// moveCursor moves the selection by delta rows, stopping at either end.
+func (m *Model) moveCursor(delta int) {
+	m.Cursor = max(min(m.Cursor+delta, len(m.Tasks)-1), 0)
+}
+
+// viewport shows as many of rows as fit in the list container, scrolled
+// so that rows[focus] is on screen. When they don't all fit, the first
+// and last lines say how many rows are hidden above and below, and a
+// scroll bar runs down the right edge.
+func (m *Model) viewport(t themes.Theme, rows []string, focus int) string {
+	height, width := m.listHeight(), m.layout().width
+	heights := make([]int, len(rows))
+	total := 0
+	for i, r := range rows {
+		heights[i] = lipgloss.Height(r)
+		total += heights[i]
+	}
+	if total <= height || height < 3 {
+		m.offset = 0
+		return strings.Join(rows, "\n")
+	}
+
+	// Keep a line for each hint, then scroll just far enough to show focus.
+	room := height - 2
+	focus = max(min(focus, len(rows)-1), 0)
+	m.offset = min(m.offset, focus)
+	for m.offset < focus && sum(heights[m.offset:focus+1]) > room {
+		m.offset++
+	}
+	end, used := m.offset, 0
+	for end < len(rows) && (end == m.offset || used+heights[end] <= room) {
+		used += heights[end]
+		end++
+	}
+	// At the bottom, fill any space left by scrolling back up.
+	for end == len(rows) && m.offset > 0 && used+heights[m.offset-1] <= room {
+		m.offset--
+		used += heights[m.offset]
+	}
+
+	hint := func(n int, arrow, key string) string {
+		if n == 0 {
+			return ""
+		}
+		return m.zone(target{Key: key}, styles.HelpStyle.Render(fmt.Sprintf("  %s %d more", arrow, n)))
+	}
+	// A row taller than the room is cut short rather than the hint below.
+	shown := strings.Join(rows[m.offset:end], "\n")
+	if used > room {
+		shown = strings.Join(strings.Split(shown, "\n")[:room], "\n")
+	}
+	list := lipgloss.JoinVertical(lipgloss.Left,
+		hint(m.offset, "↑", "pgup"),
+		shown,
+		hint(len(rows)-end, "↓", "pgdown"),
+	)
+	list = lipgloss.NewStyle().Width(width - 1).Height(height).MaxHeight(height).Render(list)
+	return lipgloss.JoinHorizontal(lipgloss.Top, list, scrollBar(t, height, m.offset, end-m.offset, len(rows)))
+}
+
+// scrollBar draws a track height lines tall with a thumb for the shown
+// rows out of total, starting at row first.
+func scrollBar(t themes.Theme, height, first, shown, total int) string {
+	thumb := max(height*shown/total, 1)
+	top := 0
+	if hidden := total - shown; hidden > 0 {
+		top = (height - thumb) * first / hidden
+	}
+	track := lipgloss.NewStyle().Foreground(t.Dim)
+	bar := lipgloss.NewStyle().Foreground(t.Accent)
+	lines := make([]string, height)
+	for i := range lines {
+		if i >= top && i < top+thumb {
+			lines[i] = bar.Render("┃")
+		} else {
+			lines[i] = track.Render("│")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func sum(xs []int) int {
+	n := 0
+	for _, x := range xs {
+		n += x
+	}
+	return n
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/nirabyte/todo/internal/themes"
+)
+
+// numbered makes n rows of lines lines each, naming each line after its row.
+func numbered(n, lines int) []string {
+	rows := make([]string, n)
+	for i := range rows {
+		parts := make([]string, lines)
+		for j := range parts {
+			parts[j] = fmt.Sprintf("row %d.%d", i, j)
+		}
+		rows[i] = strings.Join(parts, "\n")
+	}
+	return rows
+}
+
+func TestViewportOffset(t *testing.T) {
+	// A 16 line window leaves 9 lines for the list: 7 rows and two hints.
+	for _, tc := range []struct {
+		name        string
+		rows        []string
+		offset      int
+		focus       int
+		wantOffset  int
+		first, last string
+	}{
+		{"top", numbered(20, 1), 0, 0, 0, "row 0.0", "↓ 13 more"},
+		{"bottom", numbered(20, 1), 0, 19, 13, "↑ 13 more", "row 19.0"},
+		{"back up", numbered(20, 1), 13, 2, 2, "↑ 2 more", "↓ 11 more"},
+		{"stays put", numbered(20, 1), 5, 8, 5, "↑ 5 more", "↓ 8 more"},
+		{"multi-line top", numbered(10, 2), 0, 0, 0, "row 0.0", "↓ 7 more"},
+		{"multi-line bottom", numbered(10, 2), 0, 9, 7, "↑ 7 more", "row 9.1"},
+		{"taller than the room", numbered(3, 8), 0, 1, 1, "↑ 1 more", "↓ 1 more"},
+	} {
+		m := &Model{Width: 80, Height: 16, offset: tc.offset}
+		out := ansi.Strip(m.viewport(themes.All[0], tc.rows, tc.focus))
+		if m.offset != tc.wantOffset {
+			t.Errorf("%s: offset = %d, want %d", tc.name, m.offset, tc.wantOffset)
+		}
+		if h := lipgloss.Height(out); h != m.listHeight() {
+			t.Errorf("%s: %d lines, want %d", tc.name, h, m.listHeight())
+		}
+		lines := strings.Split(out, "\n")
+		var texts []string
+		for _, l := range lines {
+			if l = strings.TrimSpace(strings.TrimRight(l, "│┃")); l != "" {
+				texts = append(texts, l)
+			}
+		}
+		if len(texts) == 0 || texts[0] != tc.first || texts[len(texts)-1] != tc.last {
+			t.Errorf("%s: view\n%s\nwant it to run from %q to %q", tc.name, out, tc.first, tc.last)
+		}
+	}
+}
+
+func TestViewportTooShort(t *testing.T) {
+	// Under three lines there is no room for hints, so the rows are left
+	// for the container to clip.
+	m := &Model{Width: 80, Height: 9, offset: 4}
+	if h := m.listHeight(); h != 2 {
+		t.Fatalf("listHeight = %d", h)
+	}
+	rows := numbered(5, 1)
+	if out := m.viewport(themes.All[0], rows, 4); out != strings.Join(rows, "\n") || m.offset != 0 {
+		t.Errorf("offset %d, view:\n%s", m.offset, out)
+	}
+}
+
+func TestCursorKeys(t *testing.T) {
+	t.Chdir(t.TempDir())
+	m := &Model{Width: 80, Height: 16}
+	for i := range 30 {
+		m.Tasks = append(m.Tasks, Task{ID: int64(i + 1), Title: fmt.Sprintf("Task %d", i+1)})
+	}
+	// The list is 9 lines tall, so half a page is 4 rows.
+	for _, tc := range []struct {
+		key  string
+		want int
+	}{
+		{"G", 29},
+		{"g", 0},
+		{"ctrl+d", 4},
+		{"pgdown", 13},
+		{"pgdown", 22},
+		{"pgdown", 29},
+		{"ctrl+d", 29},
+		{"ctrl+u", 25},
+		{"pgup", 16},
+		{"end", 29},
+		{"home", 0},
+		{"ctrl+u", 0},
+		{"pgup", 0},
+	} {
+		m.Update(keyPress(tc.key))
+		if m.Cursor != tc.want {
+			t.Fatalf("after %s, cursor = %d, want %d", tc.key, m.Cursor, tc.want)
+		}
+	}
+}
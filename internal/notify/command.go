@@ -0,0 +1,37 @@
+package notify
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Command runs a shell command for each notification. The details are
+// passed in TODO_TITLE, TODO_BODY, TODO_ID, TODO_DUE and TODO_TAGS.
+type Command struct {
+	Cmd string
+}
+
+func (c Command) Notify(n Notification) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", c.Cmd)
+	} else {
+		cmd = exec.Command("sh", "-c", c.Cmd)
+	}
+	due := ""
+	if !n.Due.IsZero() {
+		due = n.Due.Format(time.RFC3339)
+	}
+	cmd.Env = append(os.Environ(),
+		"TODO_TITLE="+n.Title,
+		"TODO_BODY="+n.Body,
+		"TODO_ID="+strconv.FormatInt(n.TaskID, 10),
+		"TODO_DUE="+due,
+		"TODO_TAGS="+strings.Join(n.Tags, ","),
+	)
+	return cmd.Run()
+}
@@ -0,0 +1,71 @@
+package notify
+
+import (
+	"fmt"
+
+	"github.com/nirabyte/todo/internal/config"
+)
+
+// FromSettings builds the router described by the notifications settings.
+func FromSettings(s config.Notifications) (*Router, error) {
+	r := &Router{Sinks: map[string]Notifier{}, Default: s.Default}
+	for name, sink := range s.Sinks {
+		n, err := newSink(sink)
+		if err != nil {
+			return nil, fmt.Errorf("notifier %q: %w", name, err)
+		}
+		r.Sinks[name] = n
+	}
+	for _, route := range s.Routes {
+		r.Routes = append(r.Routes, Route{Tag: route.Tag, Sinks: route.Sinks})
+	}
+
+	for _, name := range r.Default {
+		if _, ok := r.Sinks[name]; !ok {
+			return nil, fmt.Errorf("default notifier %q is not defined", name)
+		}
+	}
+	for _, route := range r.Routes {
+		for _, name := range route.Sinks {
+			if _, ok := r.Sinks[name]; !ok {
+				return nil, fmt.Errorf("route for #%s: notifier %q is not defined", route.Tag, name)
+			}
+		}
+	}
+	return r, nil
+}
+
+func newSink(s config.Sink) (Notifier, error) {
+	switch s.Type {
+	case "desktop":
+		return Desktop{}, nil
+	case "terminal":
+		switch s.Mode {
+		case "", TerminalBell, TerminalOSC9, TerminalOSC777:
+			return Terminal{Mode: s.Mode}, nil
+		}
+		return nil, fmt.Errorf("unknown terminal mode %q", s.Mode)
+	case "command":
+		if s.Command == "" {
+			return nil, fmt.Errorf("command is empty")
+		}
+		return Command{Cmd: s.Command}, nil
+	case "webhook":
+		if s.URL == "" {
+			return nil, fmt.Errorf("url is empty")
+		}
+		switch s.Template {
+		case "", TemplateJSON, TemplateSlack, TemplateDiscord, TemplateNtfy:
+			return Webhook{URL: s.URL, Template: s.Template}, nil
+		}
+		return nil, fmt.Errorf("unknown webhook template %q", s.Template)
+	case "email":
+		if s.SMTP == "" || s.From == "" || len(s.To) == 0 {
+			return nil, fmt.Errorf("smtp, from and to are required")
+		}
+		return Email{Addr: s.SMTP, From: s.From, To: s.To, Username: s.Username, Password: s.Password}, nil
+	case "fake":
+		return &Recorder{}, nil
+	}
+	return nil, fmt.Errorf("unknown type %q", s.Type)
+}
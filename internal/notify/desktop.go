@@ -0,0 +1,22 @@
+package notify
+
+import (
+	"errors"
+
+	"github.com/gen2brain/beeep"
+)
+
+var errNoActions = errors.New("actionable notifications are not supported here")
+
+// Desktop shows a native desktop notification. Where the desktop supports
+// it, the notification's actions appear as buttons.
+type Desktop struct{}
+
+func (Desktop) Notify(n Notification) error {
+	if len(n.Actions) > 0 && n.OnAction != nil {
+		if err := notifyWithActions(n); err == nil {
+			return nil
+		}
+	}
+	return beeep.Notify(n.Title, n.Body, "")
+}
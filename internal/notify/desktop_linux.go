@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"sync"
+	"time"
+
+	dbusnotify "github.com/esiqveland/notify"
+	"github.com/godbus/dbus/v5"
+)
+
+// The session bus connection stays open for the life of the process so
+// that button presses on earlier notifications can still be delivered.
+var desktop struct {
+	once     sync.Once
+	err      error
+	notifier dbusnotify.Notifier
+
+	mu       sync.Mutex
+	handlers map[uint32]func(key string)
+}
+
+func notifyWithActions(n Notification) error {
+	desktop.once.Do(func() {
+		conn, err := dbus.SessionBusPrivate()
+		if err == nil {
+			err = conn.Auth(nil)
+		}
+		if err == nil {
+			err = conn.Hello()
+		}
+		if err != nil {
+			desktop.err = err
+			return
+		}
+		desktop.handlers = map[uint32]func(string){}
+		desktop.notifier, desktop.err = dbusnotify.New(conn,
+			dbusnotify.WithOnAction(onAction),
+			dbusnotify.WithOnClosed(onClosed),
+		)
+	})
+	if desktop.err != nil {
+		return desktop.err
+	}
+
+	note := dbusnotify.Notification{
+		AppName:       "todo",
+		Summary:       n.Title,
+		Body:          n.Body,
+		ExpireTimeout: 30 * time.Second,
+	}
+	for _, a := range n.Actions {
+		note.Actions = append(note.Actions, dbusnotify.Action{Key: a.Key, Label: a.Label})
+	}
+
+	desktop.mu.Lock()
+	defer desktop.mu.Unlock()
+	id, err := desktop.notifier.SendNotification(note)
+	if err != nil {
+		return err
+	}
+	desktop.handlers[id] = n.OnAction
+	return nil
+}
+
+func onAction(s *dbusnotify.ActionInvokedSignal) {
+	desktop.mu.Lock()
+	h := desktop.handlers[s.ID]
+	delete(desktop.handlers, s.ID)
+	desktop.mu.Unlock()
+	if h != nil && s.ActionKey != "default" {
+		h(s.ActionKey)
+	}
+}
+
+func onClosed(s *dbusnotify.NotificationClosedSignal) {
+	desktop.mu.Lock()
+	delete(desktop.handlers, s.ID)
+	desktop.mu.Unlock()
+}
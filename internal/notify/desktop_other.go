@@ -0,0 +1,7 @@
+//go:build !linux
+
+package notify
+
+func notifyWithActions(n Notification) error {
+	return errNoActions
+}
@@ -0,0 +1,47 @@
+package notify
+
+import (
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// Email sends each notification as a plain-text message through an SMTP
+// server. Username, when set, authenticates with PLAIN.
+type Email struct {
+	Addr     string // host:port
+	From     string
+	To       []string
+	Username string
+	Password string
+}
+
+func (e Email) Notify(n Notification) error {
+	var auth smtp.Auth
+	if e.Username != "" {
+		host, _, err := net.SplitHostPort(e.Addr)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", e.Username, e.Password, host)
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", e.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(e.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", headerSafe(n.Title+": "+n.Body)))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(n.Body + "\r\n")
+	if !n.Due.IsZero() {
+		fmt.Fprintf(&msg, "\r\nDue: %s\r\n", n.Due.Format(time.RFC1123))
+	}
+	return smtp.SendMail(e.Addr, auth, e.From, e.To, []byte(msg.String()))
+}
+
+func headerSafe(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
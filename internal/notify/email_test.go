@@ -0,0 +1,165 @@
+package notify
+
+import (
+	"bufio"
+	"encoding/base64"
+	"mime"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+// smtpServer is just enough of an SMTP server for net/smtp: it takes one
+// message at a time and hands over the envelope, the data and the PLAIN
+// credentials, if any.
+type smtpServer struct {
+	addr string
+	got  chan smtpMessage
+}
+
+type smtpMessage struct {
+	from, auth string
+	to         []string
+	data       string
+}
+
+func newSMTPServer(t *testing.T) *smtpServer {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	s := &smtpServer{addr: l.Addr().String(), got: make(chan smtpMessage, 1)}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	say := func(line string) { conn.Write([]byte(line + "\r\n")) }
+	var msg smtpMessage
+	say("220 localhost ready")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			say("250-localhost")
+			say("250 AUTH PLAIN")
+		case "AUTH":
+			creds, _ := strings.CutPrefix(arg, "PLAIN ")
+			b, _ := base64.StdEncoding.DecodeString(creds)
+			msg.auth = string(b)
+			say("235 ok")
+		case "MAIL":
+			msg.from = strings.Trim(strings.TrimPrefix(arg, "FROM:"), "<>")
+			say("250 ok")
+		case "RCPT":
+			msg.to = append(msg.to, strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>"))
+			say("250 ok")
+		case "DATA":
+			say("354 go on")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			msg.data = data.String()
+			s.got <- msg
+			msg = smtpMessage{}
+			say("250 queued")
+		case "QUIT":
+			say("221 bye")
+			return
+		default:
+			say("250 ok")
+		}
+	}
+}
+
+func TestEmail(t *testing.T) {
+	srv := newSMTPServer(t)
+	e := Email{Addr: srv.addr, From: "todo@example.org", To: []string{"me@example.org", "you@example.org"}, Username: "me", Password: "pw"}
+	due := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	if err := e.Notify(Notification{Title: "Réunion", Body: "Buy oat milk 🥛\r\nBcc: evil@example.org", Due: due}); err != nil {
+		t.Fatal(err)
+	}
+	got := <-srv.got
+	if got.from != "todo@example.org" || strings.Join(got.to, ",") != "me@example.org,you@example.org" {
+		t.Errorf("envelope from %q to %q", got.from, got.to)
+	}
+	if got.auth != "\x00me\x00pw" {
+		t.Errorf("auth %q", got.auth)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(got.data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Header.Get("Bcc") != "" {
+		t.Error("the body added a header")
+	}
+	raw := msg.Header.Get("Subject")
+	for _, r := range raw {
+		if r > 0x7f {
+			t.Errorf("subject %q isn't encoded", raw)
+			break
+		}
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(raw)
+	if want := "Réunion: Buy oat milk 🥛  Bcc: evil@example.org"; err != nil || subject != want {
+		t.Errorf("subject = %q, %v; want %q", subject, err, want)
+	}
+	if !strings.Contains(got.data, "Due: Mon, 02 Mar 2026 09:00:00 UTC") {
+		t.Errorf("no due date in:\n%s", got.data)
+	}
+}
+
+func TestEmailPlainSubject(t *testing.T) {
+	srv := newSMTPServer(t)
+	e := Email{Addr: srv.addr, From: "todo@example.org", To: []string{"me@example.org"}}
+	if err := e.Notify(Notification{Title: "Call mum", Body: "Due now"}); err != nil {
+		t.Fatal(err)
+	}
+	got := <-srv.got
+	if got.auth != "" {
+		t.Errorf("authenticated without a username: %q", got.auth)
+	}
+	if !strings.Contains(got.data, "Subject: Call mum: Due now\r\n") {
+		t.Errorf("message:\n%s", got.data)
+	}
+}
+
+func TestEmailServerDown(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+	if err := (Email{Addr: addr, From: "a@example.org", To: []string{"b@example.org"}}).Notify(Notification{}); err == nil {
+		t.Error("no error with no server")
+	}
+}
@@ -0,0 +1,116 @@
+// Package notify delivers reminders to one or more sinks: the desktop,
+// the terminal, a shell command, a webhook or email.
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type Notification struct {
+	Title  string
+	Body   string
+	TaskID int64
+	Tags   []string
+	Due    time.Time
+
+	// Sinks names the sinks this task asked for. Empty means route by tag.
+	Sinks []string
+
+	// Actions are offered as buttons by sinks that support them, which
+	// report the chosen key to OnAction.
+	Actions  []Action
+	OnAction func(key string)
+}
+
+type Action struct {
+	Key   string
+	Label string
+}
+
+type Notifier interface {
+	Notify(n Notification) error
+}
+
+// Recorder keeps every notification in memory instead of delivering it.
+// It stands in for real sinks in tests and dry runs. Fail makes it act
+// like a sink that is down.
+type Recorder struct {
+	mu   sync.Mutex
+	sent []Notification
+	err  error
+}
+
+func (r *Recorder) Notify(n Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	r.sent = append(r.sent, n)
+	return nil
+}
+
+// Fail makes Notify return err from now on, or succeed again if err is
+// nil.
+func (r *Recorder) Fail(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.err = err
+}
+
+func (r *Recorder) Sent() []Notification {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Notification(nil), r.sent...)
+}
+
+// Route sends notifications for tasks tagged Tag to Sinks.
+type Route struct {
+	Tag   string
+	Sinks []string
+}
+
+// Router picks the sinks for each notification: the task's own choice
+// first, then every route matching one of its tags, then Default.
+type Router struct {
+	Sinks   map[string]Notifier
+	Routes  []Route
+	Default []string
+}
+
+func (r *Router) Notify(n Notification) error {
+	names := n.Sinks
+	if len(names) == 0 {
+		for _, route := range r.Routes {
+			for _, tag := range n.Tags {
+				if tag == route.Tag {
+					names = append(names, route.Sinks...)
+				}
+			}
+		}
+	}
+	if len(names) == 0 {
+		names = r.Default
+	}
+
+	var errs []error
+	seen := map[string]bool{}
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		sink, ok := r.Sinks[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown notifier %q", name))
+			continue
+		}
+		if err := sink.Notify(n); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
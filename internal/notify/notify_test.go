@@ -0,0 +1,151 @@
+package notify
+
+import (
+	"bytes"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nirabyte/todo/internal/config"
+)
+
+func TestRouter(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		n    Notification
+		want []string
+	}{
+		{"no tags", Notification{}, []string{"desk"}},
+		{"routed", Notification{Tags: []string{"work"}}, []string{"desk", "mail"}},
+		{"two routes", Notification{Tags: []string{"urgent", "work"}}, []string{"desk", "mail", "phone"}},
+		{"task's own", Notification{Tags: []string{"work"}, Sinks: []string{"phone"}}, []string{"phone"}},
+		{"unrouted tag", Notification{Tags: []string{"home"}}, []string{"desk"}},
+	} {
+		recorders := map[string]*Recorder{"desk": {}, "phone": {}, "mail": {}}
+		r := &Router{
+			Sinks: map[string]Notifier{},
+			Routes: []Route{
+				{Tag: "work", Sinks: []string{"mail", "desk"}},
+				{Tag: "urgent", Sinks: []string{"phone", "desk"}},
+			},
+			Default: []string{"desk"},
+		}
+		for name, rec := range recorders {
+			r.Sinks[name] = rec
+		}
+		if err := r.Notify(tc.n); err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+		}
+		var got []string
+		for _, name := range slices.Sorted(maps.Keys(recorders)) {
+			switch len(recorders[name].Sent()) {
+			case 0:
+			case 1:
+				got = append(got, name)
+			default:
+				t.Errorf("%s: sent to %s more than once", tc.name, name)
+			}
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("%s: went to %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestRouterErrors(t *testing.T) {
+	ok, down := &Recorder{}, &Recorder{}
+	down.Fail(errors.New("connection refused"))
+	r := &Router{Sinks: map[string]Notifier{"ok": ok, "down": down}, Default: []string{"down", "ok", "gone"}}
+	err := r.Notify(Notification{Title: "Call mum"})
+	if err == nil || !strings.Contains(err.Error(), "down: connection refused") || !strings.Contains(err.Error(), `unknown notifier "gone"`) {
+		t.Errorf("err = %v", err)
+	}
+	// One sink failing doesn't stop the others.
+	if len(ok.Sent()) != 1 {
+		t.Errorf("working sink got %d notifications", len(ok.Sent()))
+	}
+}
+
+func TestFromSettings(t *testing.T) {
+	r, err := FromSettings(config.Notifications{
+		Sinks: map[string]config.Sink{
+			"bell": {Type: "terminal"},
+			"hook": {Type: "webhook", URL: "https://example.org", Template: "ntfy"},
+			"mail": {Type: "email", SMTP: "mail:25", From: "a@example.org", To: []string{"b@example.org"}},
+		},
+		Routes:  []config.Route{{Tag: "work", Sinks: []string{"mail"}}},
+		Default: []string{"bell"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.Sinks["hook"].(Webhook); !ok || len(r.Routes) != 1 {
+		t.Errorf("router = %+v", r)
+	}
+
+	for _, tc := range []struct {
+		s    config.Notifications
+		want string
+	}{
+		{config.Notifications{Default: []string{"nope"}}, `default notifier "nope"`},
+		{config.Notifications{Routes: []config.Route{{Tag: "work", Sinks: []string{"nope"}}}}, `route for #work`},
+		{config.Notifications{Sinks: map[string]config.Sink{"x": {Type: "pager"}}}, `unknown type "pager"`},
+		{config.Notifications{Sinks: map[string]config.Sink{"x": {Type: "terminal", Mode: "beep"}}}, `unknown terminal mode`},
+		{config.Notifications{Sinks: map[string]config.Sink{"x": {Type: "webhook"}}}, `url is empty`},
+		{config.Notifications{Sinks: map[string]config.Sink{"x": {Type: "webhook", URL: "u", Template: "teams"}}}, `unknown webhook template`},
+		{config.Notifications{Sinks: map[string]config.Sink{"x": {Type: "email", SMTP: "mail:25"}}}, `required`},
+		{config.Notifications{Sinks: map[string]config.Sink{"x": {Type: "command"}}}, `command is empty`},
+	} {
+		if _, err := FromSettings(tc.s); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%+v: err = %v, want %q", tc.s, err, tc.want)
+		}
+	}
+}
+
+func TestTerminal(t *testing.T) {
+	n := Notification{Title: "Call mum", Body: "Due now;\x07 really"}
+	for _, tc := range []struct {
+		mode, want string
+	}{
+		{"", "\a"},
+		{TerminalBell, "\a"},
+		{TerminalOSC9, "\x1b]9;Call mum: Due now   really\x07"},
+		{TerminalOSC777, "\x1b]777;notify;Call mum;Due now   really\x07"},
+	} {
+		var b bytes.Buffer
+		if err := (Terminal{Mode: tc.mode, Out: &b}).Notify(n); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != tc.want {
+			t.Errorf("mode %q wrote %q, want %q", tc.mode, b.String(), tc.want)
+		}
+	}
+}
+
+func TestCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	out := filepath.Join(t.TempDir(), "out")
+	c := Command{Cmd: `printf '%s|%s|%s|%s|%s' "$TODO_TITLE" "$TODO_BODY" "$TODO_ID" "$TODO_DUE" "$TODO_TAGS" > ` + out}
+	due := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	if err := c.Notify(Notification{Title: "Call mum", Body: "it's time", TaskID: 7, Due: due, Tags: []string{"home", "family"}}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Call mum|it's time|7|2026-03-02T09:00:00Z|home,family"; string(got) != want {
+		t.Errorf("command saw %q, want %q", got, want)
+	}
+	if err := (Command{Cmd: "exit 3"}).Notify(Notification{}); err == nil {
+		t.Error("failing command reported no error")
+	}
+}
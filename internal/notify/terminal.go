@@ -0,0 +1,49 @@
+package notify
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	TerminalBell   = "bell"
+	TerminalOSC9   = "osc9"
+	TerminalOSC777 = "osc777"
+)
+
+// Terminal rings the bell or raises a notification through the terminal
+// emulator with OSC 9 (iTerm2, Windows Terminal, kitty) or OSC 777
+// (rxvt, foot, WezTerm).
+type Terminal struct {
+	Mode string
+	Out  io.Writer
+}
+
+func (t Terminal) Notify(n Notification) error {
+	out := t.Out
+	if out == nil {
+		out = os.Stderr
+	}
+	var err error
+	switch t.Mode {
+	case TerminalOSC9:
+		_, err = fmt.Fprintf(out, "\x1b]9;%s: %s\x07", oscSafe(n.Title), oscSafe(n.Body))
+	case TerminalOSC777:
+		_, err = fmt.Fprintf(out, "\x1b]777;notify;%s;%s\x07", oscSafe(n.Title), oscSafe(n.Body))
+	default:
+		_, err = io.WriteString(out, "\a")
+	}
+	return err
+}
+
+// oscSafe strips the bytes that would end the escape sequence early.
+func oscSafe(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == ';' {
+			return ' '
+		}
+		return r
+	}, s)
+}
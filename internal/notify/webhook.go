@@ -0,0 +1,85 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	TemplateJSON    = "json"
+	TemplateSlack   = "slack"
+	TemplateDiscord = "discord"
+	TemplateNtfy    = "ntfy"
+)
+
+// Webhook posts each notification to URL, shaped by Template for Slack,
+// Discord, ntfy or, by default, as a plain JSON object.
+type Webhook struct {
+	URL      string
+	Template string
+	Client   *http.Client
+}
+
+func (w Webhook) Notify(n Notification) error {
+	client := w.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	var req *http.Request
+	var err error
+	text := n.Title + ": " + n.Body
+	switch w.Template {
+	case TemplateNtfy:
+		// ntfy takes the message as the body and the title as a header.
+		req, err = http.NewRequest(http.MethodPost, w.URL, bytes.NewBufferString(n.Body))
+		if err == nil {
+			req.Header.Set("Title", n.Title)
+			req.Header.Set("Tags", "alarm_clock")
+		}
+	case TemplateSlack:
+		req, err = jsonRequest(w.URL, map[string]string{"text": text})
+	case TemplateDiscord:
+		req, err = jsonRequest(w.URL, map[string]string{"content": text})
+	default:
+		payload := map[string]any{
+			"title": n.Title,
+			"body":  n.Body,
+			"id":    n.TaskID,
+			"tags":  n.Tags,
+		}
+		if !n.Due.IsZero() {
+			payload["due"] = n.Due.Format(time.RFC3339)
+		}
+		req, err = jsonRequest(w.URL, payload)
+	}
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s: %s", w.URL, resp.Status)
+	}
+	return nil
+}
+
+func jsonRequest(url string, payload any) (*http.Request, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
@@ -0,0 +1,73 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebhookTemplates(t *testing.T) {
+	type request struct {
+		header http.Header
+		body   string
+	}
+	got := make(chan request, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got <- request{r.Header, string(body)}
+	}))
+	defer srv.Close()
+
+	n := Notification{Title: "Call mum", Body: "Due now", TaskID: 7, Tags: []string{"home"}, Due: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)}
+	for _, tc := range []struct {
+		template string
+		want     map[string]any
+	}{
+		{"", map[string]any{"title": "Call mum", "body": "Due now", "id": 7.0, "tags": []any{"home"}, "due": "2026-03-02T09:00:00Z"}},
+		{TemplateSlack, map[string]any{"text": "Call mum: Due now"}},
+		{TemplateDiscord, map[string]any{"content": "Call mum: Due now"}},
+	} {
+		if err := (Webhook{URL: srv.URL, Template: tc.template}).Notify(n); err != nil {
+			t.Fatalf("template %q: %v", tc.template, err)
+		}
+		r := <-got
+		if ct := r.header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("template %q: content type %q", tc.template, ct)
+		}
+		var payload map[string]any
+		if err := json.Unmarshal([]byte(r.body), &payload); err != nil {
+			t.Fatal(err)
+		}
+		want, _ := json.Marshal(tc.want)
+		have, _ := json.Marshal(payload)
+		if string(want) != string(have) {
+			t.Errorf("template %q posted %s, want %s", tc.template, have, want)
+		}
+	}
+
+	if err := (Webhook{URL: srv.URL, Template: TemplateNtfy}).Notify(n); err != nil {
+		t.Fatal(err)
+	}
+	if r := <-got; r.body != "Due now" || r.header.Get("Title") != "Call mum" {
+		t.Errorf("ntfy got %q with title %q", r.body, r.header.Get("Title"))
+	}
+}
+
+func TestWebhookFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusBadGateway)
+	}))
+	defer srv.Close()
+	err := (Webhook{URL: srv.URL}).Notify(Notification{Title: "Call mum"})
+	if err == nil || !strings.Contains(err.Error(), "502 Bad Gateway") {
+		t.Errorf("err = %v", err)
+	}
+	srv.Close()
+	if err := (Webhook{URL: srv.URL}).Notify(Notification{Title: "Call mum"}); err == nil {
+		t.Error("no error from a server that is down")
+	}
+}
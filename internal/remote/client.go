@@ -0,0 +1,61 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nirabyte/todo/internal/models"
+)
+
+// Client talks to a sync server.
+type Client struct {
+	URL   string
+	Token string
+	HTTP  *http.Client
+}
+
+// Sync sends data to the server and returns it merged with the server's
+// list, with SyncedAt set. While the server can't be reached, changes stay
+// in the data file with their clocks and go out with the next Sync.
+func (c *Client) Sync(ctx context.Context, data models.AppData) (models.AppData, error) {
+	start := time.Now()
+	body, err := json.Marshal(replicaOf(data))
+	if err != nil {
+		return data, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(c.URL, "/")+"/v1/sync", bytes.NewReader(body))
+	if err != nil {
+		return data, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	client := c.HTTP
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return data, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return data, fmt.Errorf("sync: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	var theirs Replica
+	if err := json.NewDecoder(resp.Body).Decode(&theirs); err != nil {
+		return data, fmt.Errorf("sync: %w", err)
+	}
+	merged := models.Merge(data, theirs.data())
+	merged.Tombstones = models.PruneTombstones(merged.Tombstones, start)
+	merged.SyncedAt = start
+	return merged, nil
+}
@@ -0,0 +1,22 @@
+// Package remote shares a task list between machines through a small HTTP
+// server. Replicas send their whole list with its change clocks and get
+// back the server's merge of everything it has seen; see models.Merge.
+package remote
+
+import (
+	"github.com/nirabyte/todo/internal/models"
+)
+
+// Replica is what a sync request and response carry.
+type Replica struct {
+	Tasks      []models.Task      `json:"tasks"`
+	Tombstones []models.Tombstone `json:"tombstones"`
+}
+
+func replicaOf(data models.AppData) Replica {
+	return Replica{Tasks: data.Tasks, Tombstones: data.Tombstones}
+}
+
+func (r Replica) data() models.AppData {
+	return models.AppData{Tasks: r.Tasks, Tombstones: r.Tombstones}
+}
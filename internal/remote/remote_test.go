@@ -0,0 +1,233 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nirabyte/todo/internal/config"
+	"github.com/nirabyte/todo/internal/control"
+	"github.com/nirabyte/todo/internal/models"
+)
+
+// memStore is a Store in memory.
+type memStore struct {
+	mu   sync.Mutex
+	data models.AppData
+}
+
+func (s *memStore) Load() (models.AppData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data, nil
+}
+
+func (s *memStore) Save(data models.AppData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data = data
+	return nil
+}
+
+// edit applies fn to a copy of data and stamps the changes at now, as
+// WriteData does on a replica that syncs.
+func edit(data models.AppData, now time.Time, fn func(*models.AppData)) models.AppData {
+	next := data
+	next.Tasks = append([]models.Task(nil), data.Tasks...)
+	fn(&next)
+	models.Stamp(&data, &next, now)
+	return next
+}
+
+func titles(data models.AppData) []string {
+	var out []string
+	for _, t := range data.Tasks {
+		out = append(out, t.Title)
+	}
+	return out
+}
+
+func TestTwoReplicasConverge(t *testing.T) {
+	store := &memStore{}
+	srv := httptest.NewServer(Handler(store, "secret"))
+	defer srv.Close()
+	c := &Client{URL: srv.URL + "/", Token: "secret"}
+	ctx := context.Background()
+	// Recent, so the server doesn't prune the tombstones as too old.
+	t0 := time.Now().Add(-time.Hour).Truncate(time.Minute)
+
+	// Laptop adds two tasks and syncs; the desktop starts empty.
+	laptop := edit(models.AppData{}, t0, func(d *models.AppData) {
+		d.Tasks = []models.Task{{ID: 1, Title: "Buy milk"}, {ID: 2, Title: "Call mum"}}
+	})
+	laptop, err := c.Sync(ctx, laptop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if laptop.SyncedAt.IsZero() {
+		t.Error("SyncedAt not set")
+	}
+	desktop, err := c.Sync(ctx, models.AppData{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := titles(desktop); len(got) != 2 {
+		t.Fatalf("desktop has %q", got)
+	}
+
+	// Offline, the laptop renames a task and the desktop deletes the other
+	// and checks the renamed one off. Both changes survive.
+	laptop = edit(laptop, t0.Add(time.Minute), func(d *models.AppData) {
+		d.Tasks[0].Title = "Buy oat milk"
+	})
+	desktop = edit(desktop, t0.Add(2*time.Minute), func(d *models.AppData) {
+		d.Tasks = []models.Task{d.Tasks[0]}
+		d.Tasks[0].Done = true
+	})
+	if laptop, err = c.Sync(ctx, laptop); err != nil {
+		t.Fatal(err)
+	}
+	if desktop, err = c.Sync(ctx, desktop); err != nil {
+		t.Fatal(err)
+	}
+	if laptop, err = c.Sync(ctx, laptop); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, d := range map[string]models.AppData{"laptop": laptop, "desktop": desktop, "server": store.data} {
+		if len(d.Tasks) != 1 || d.Tasks[0].Title != "Buy oat milk" || !d.Tasks[0].Done {
+			t.Errorf("%s has %+v", name, d.Tasks)
+		}
+		if len(d.Tombstones) != 1 || d.Tombstones[0].ID != 2 {
+			t.Errorf("%s tombstones = %+v", name, d.Tombstones)
+		}
+	}
+}
+
+func TestHandlerRequiresToken(t *testing.T) {
+	store := &memStore{data: models.AppData{Tasks: []models.Task{{ID: 1, Title: "Private"}}}}
+	srv := httptest.NewServer(Handler(store, "secret"))
+	defer srv.Close()
+
+	for _, token := range []string{"", "wrong"} {
+		c := &Client{URL: srv.URL, Token: token}
+		data := models.AppData{Tasks: []models.Task{{ID: 9, Title: "Injected"}}}
+		got, err := c.Sync(context.Background(), data)
+		if err == nil || !strings.Contains(err.Error(), "401") {
+			t.Errorf("token %q: err = %v, want a 401", token, err)
+		}
+		if len(got.Tasks) != 1 || got.Tasks[0].ID != 9 {
+			t.Errorf("token %q: a failed sync changed the list to %+v", token, got.Tasks)
+		}
+
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/v1/tasks", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("token %q: GET /v1/tasks = %s", token, resp.Status)
+		}
+	}
+	if got := titles(store.data); len(got) != 1 || got[0] != "Private" {
+		t.Errorf("store now has %q", got)
+	}
+}
+
+func TestHandlerList(t *testing.T) {
+	store := &memStore{data: models.AppData{Tasks: []models.Task{{ID: 1, Title: "Shared"}}}}
+	srv := httptest.NewServer(Handler(store, ""))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/v1/tasks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var r Replica
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Tasks) != 1 || r.Tasks[0].Title != "Shared" {
+		t.Errorf("GET /v1/tasks = %+v", r)
+	}
+}
+
+func TestHandlerRejectsBadBody(t *testing.T) {
+	srv := httptest.NewServer(Handler(&memStore{}, ""))
+	defer srv.Close()
+	resp, err := http.Post(srv.URL+"/v1/sync", "application/json", strings.NewReader("{not json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status = %s", resp.Status)
+	}
+}
+
+func TestOldTombstonesArePruned(t *testing.T) {
+	now := time.Now()
+	old, recent := now.Add(-models.TombstoneTTL-time.Hour), now.Add(-time.Hour)
+	store := &memStore{data: models.AppData{
+		Tasks:      []models.Task{{ID: 1, Title: "Kept"}},
+		Tombstones: []models.Tombstone{{ID: 2, At: old}, {ID: 3, At: recent}},
+	}}
+	srv := httptest.NewServer(Handler(store, ""))
+	defer srv.Close()
+
+	got, err := (&Client{URL: srv.URL}).Sync(context.Background(), models.AppData{
+		Tombstones: []models.Tombstone{{ID: 4, At: old}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, d := range map[string]models.AppData{"server": store.data, "client": got} {
+		if len(d.Tombstones) != 1 || d.Tombstones[0].ID != 3 {
+			t.Errorf("%s tombstones = %+v", name, d.Tombstones)
+		}
+	}
+
+	// A replica drops them too when it next writes.
+	data := edit(models.AppData{Tombstones: []models.Tombstone{{ID: 5, At: old}, {ID: 6, At: recent}}}, now, func(*models.AppData) {})
+	if len(data.Tombstones) != 1 || data.Tombstones[0].ID != 6 {
+		t.Errorf("stamped tombstones = %+v", data.Tombstones)
+	}
+}
+
+func TestFileStoreTellsAppToReload(t *testing.T) {
+	t.Chdir(t.TempDir())
+	l, err := control.Listen(control.AppSocket(config.DataFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	got := make(chan string, 1)
+	go control.Serve(l, func(cmd string) string {
+		got <- cmd
+		return "ok"
+	})
+
+	if err := (FileStore{}).Save(models.AppData{Tasks: []models.Task{{ID: 1, Title: "Synced"}}}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case cmd := <-got:
+		if cmd != control.CmdReload {
+			t.Errorf("app got %q", cmd)
+		}
+	case <-time.After(time.Second):
+		t.Error("the app wasn't told to reload")
+	}
+	data, err := (FileStore{}).Load()
+	if err != nil || len(data.Tasks) != 1 || data.Tasks[0].Title != "Synced" {
+		t.Errorf("load = %+v, %v", data.Tasks, err)
+	}
+}
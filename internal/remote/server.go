@@ -0,0 +1,112 @@
+package remote
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nirabyte/todo/internal/config"
+	"github.com/nirabyte/todo/internal/control"
+	"github.com/nirabyte/todo/internal/models"
+)
+
+// Store holds the server's copy of the list.
+type Store interface {
+	Load() (models.AppData, error)
+	Save(models.AppData) error
+}
+
+// FileStore keeps the list in the data file, like the TUI does.
+type FileStore struct{}
+
+func (FileStore) Load() (models.AppData, error) {
+	data, err := models.ReadData()
+	if errors.Is(err, os.ErrNotExist) {
+		return models.AppData{}, nil
+	}
+	return data, err
+}
+
+// Save writes data and tells a running app and the daemon to reload it.
+func (FileStore) Save(data models.AppData) error {
+	if err := models.ReplaceData(data); err != nil {
+		return err
+	}
+	control.Changed(config.DataFile)
+	return nil
+}
+
+type server struct {
+	mu    sync.Mutex
+	store Store
+	token string
+}
+
+// Handler serves the sync API from store. Requests must carry token as a
+// bearer token unless it is empty.
+//
+//	GET  /v1/tasks   the server's list
+//	POST /v1/sync    merge a Replica into the list and return the result
+func Handler(store Store, token string) http.Handler {
+	s := &server{store: store, token: token}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/tasks", s.auth(s.list))
+	mux.HandleFunc("POST /v1/sync", s.auth(s.sync))
+	return mux
+}
+
+func (s *server) auth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.token != "" {
+			got, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) != 1 {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+func (s *server) list(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	data, err := s.store.Load()
+	s.mu.Unlock()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, replicaOf(data))
+}
+
+func (s *server) sync(w http.ResponseWriter, r *http.Request) {
+	var in Replica
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 32<<20)).Decode(&in); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.store.Load()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	merged := models.Merge(data, in.data())
+	merged.Tombstones = models.PruneTombstones(merged.Tombstones, time.Now())
+	if err := s.store.Save(merged); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, replicaOf(merged))
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -16,9 +16,11 @@ var (
 	HelpStyle         lipgloss.Style
 	DueStyle          lipgloss.Style
 	OverdueStyle      lipgloss.Style
+	TrackingStyle     lipgloss.Style
 )
 
-func Update(t themes.Theme) {
+// Update rebuilds the styles for t. Reduced motion drops blinking.
+func Update(t themes.Theme, reducedMotion bool) {
 	AppStyle = lipgloss.NewStyle().Padding(1).Background(t.Bg)
 
 	HeaderStyle = lipgloss.NewStyle().
@@ -48,6 +50,7 @@ func Update(t themes.Theme) {
 	HelpStyle = lipgloss.NewStyle().Foreground(t.Dim)
 
 	DueStyle = lipgloss.NewStyle().Foreground(t.Secondary).Italic(true)
-	OverdueStyle = lipgloss.NewStyle().Foreground(t.Warning).Bold(true).Blink(true)
+	OverdueStyle = lipgloss.NewStyle().Foreground(t.Warning).Bold(true).Blink(!reducedMotion)
+	TrackingStyle = lipgloss.NewStyle().Foreground(t.Success).Bold(true)
 }
 
@@ -24,5 +24,6 @@ var All = []Theme{
 	{"One Dark", "#282c34", "#abb2bf", "#5c6370", "#61afef", "#c678dd", "#98c379", "#e06c75"},
 	{"Solarized", "#002b36", "#839496", "#586e75", "#268bd2", "#2aa198", "#859900", "#dc322f"},
 	{"Kanagawa", "#1f1f28", "#dcd7ba", "#727169", "#7e9cd8", "#957fb8", "#76946a", "#c34043"},
+	{"High Contrast", "#000000", "#ffffff", "#d0d0d0", "#ffff00", "#00ffff", "#00ff00", "#ff8080"},
 }
 